@@ -0,0 +1,93 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// Go functions called back from the HDF5 library. The preamble of a file
+// with //export directives may only contain declarations, so the C shims
+// passing these functions to HDF5 live next to the Go API using them.
+
+// #include "hdf5.h"
+// #include <stdint.h>
+import "C"
+
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+// _go_hdf5_literate_cb is the H5L_iterate_t callback used by linkNames.
+// The op_data pointer holds a cgo.Handle to a *[]string collecting the
+// link names.
+//
+//export _go_hdf5_literate_cb
+func _go_hdf5_literate_cb(group C.hid_t, name *C.char, info unsafe.Pointer, data unsafe.Pointer) C.herr_t {
+	names := cgo.Handle(uintptr(data)).Value().(*[]string)
+	*names = append(*names, C.GoString(name))
+	return 0
+}
+
+// _go_hdf5_ewalk_cb is the H5E_walk2_t callback used by newErrorStack. The
+// client_data pointer holds a cgo.Handle to a *[]ErrorRecord collecting the
+// records of the stack.
+//
+//export _go_hdf5_ewalk_cb
+func _go_hdf5_ewalk_cb(n C.uint, desc *C.H5E_error2_t, data unsafe.Pointer) C.herr_t {
+	records := cgo.Handle(uintptr(data)).Value().(*[]ErrorRecord)
+	*records = append(*records, errorRecord(desc))
+	return 0
+}
+
+// _go_hdf5_filter_cb is called by the H5Z_func_t of the Go filter
+// registered in slot.
+//
+//export _go_hdf5_filter_cb
+func _go_hdf5_filter_cb(slot C.int, flags C.uint, cd_nelmts C.size_t, cd_values *C.uint, nbytes C.size_t, buf_size *C.size_t, buf *unsafe.Pointer) C.size_t {
+	cdValues := make([]uint, int(cd_nelmts))
+	if cd_nelmts > 0 {
+		for i, v := range unsafe.Slice(cd_values, int(cd_nelmts)) {
+			cdValues[i] = uint(v)
+		}
+	}
+	return C.size_t(runGoFilter(int(slot), uint(flags), cdValues, int(nbytes), buf_size, buf))
+}
+
+// The _go_hdf5_vfd_*_cb functions implement the Go virtual file driver.
+// The handle is a cgo.Handle to the *vfdFile of the file.
+
+//export _go_hdf5_vfd_open_cb
+func _go_hdf5_vfd_open_cb(handle C.uintptr_t) C.herr_t {
+	cgo.Handle(handle).Value().(*vfdFile).retain()
+	return 0
+}
+
+//export _go_hdf5_vfd_close_cb
+func _go_hdf5_vfd_close_cb(handle C.uintptr_t) C.herr_t {
+	h := cgo.Handle(handle)
+	h.Value().(*vfdFile).release(h)
+	return 0
+}
+
+//export _go_hdf5_vfd_get_eof_cb
+func _go_hdf5_vfd_get_eof_cb(handle C.uintptr_t) C.haddr_t {
+	return C.haddr_t(cgo.Handle(handle).Value().(*vfdFile).eof())
+}
+
+//export _go_hdf5_vfd_read_cb
+func _go_hdf5_vfd_read_cb(handle C.uintptr_t, addr C.haddr_t, size C.size_t, buf unsafe.Pointer) C.herr_t {
+	vf := cgo.Handle(handle).Value().(*vfdFile)
+	if err := vf.readAt(vfdBuffer(buf, size), int64(addr)); err != nil {
+		return -1
+	}
+	return 0
+}
+
+//export _go_hdf5_vfd_write_cb
+func _go_hdf5_vfd_write_cb(handle C.uintptr_t, addr C.haddr_t, size C.size_t, buf unsafe.Pointer) C.herr_t {
+	vf := cgo.Handle(handle).Value().(*vfdFile)
+	if err := vf.writeAt(vfdBuffer(buf, size), int64(addr)); err != nil {
+		return -1
+	}
+	return 0
+}
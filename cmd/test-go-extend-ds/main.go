@@ -10,25 +10,130 @@ import (
 	"github.com/usace/go-hdf5"
 )
 
-func main() {
+const (
+	fname  string = "SDSextendible.h5"
+	dsname string = "ExtendibleArray"
+)
 
-	fname := "SDSextendible.h5"
-	//dsname:= "ExtendibleArray"
-	//NX := 10
-	//NY :=  5
-	//RANK:= 2
+func main() {
 
-	//dims := []int{3, 3} // dset dimensions at creation
-	//maxdims:= []int{hdf5.S_UNLIMITED, hdf5.S_UNLIMITED}
+	dims := []uint{3, 3} // dset dimensions at creation
+	maxdims := []uint{hdf5.S_UNLIMITED, hdf5.S_UNLIMITED}
 
-	//mspace := hdf5.CreateDataspace(dims, maxdims)
+	// create the data space with unlimited dimensions
+	space, err := hdf5.CreateSimpleDataspace(dims, maxdims)
+	if err != nil {
+		panic(err)
+	}
+	defer space.Close()
 
 	// create a new file
 	f, err := hdf5.CreateFile(fname, hdf5.F_ACC_TRUNC)
 	if err != nil {
 		panic(err)
 	}
-
+	defer f.Close()
 	fmt.Printf(":: file [%s] created\n", f.Name())
 
+	// unlimited dimensions require a chunked layout
+	dcpl, err := hdf5.NewPropList(hdf5.P_DATASET_CREATE)
+	if err != nil {
+		panic(err)
+	}
+	defer dcpl.Close()
+	err = dcpl.SetChunk([]uint{2, 5})
+	if err != nil {
+		panic(err)
+	}
+
+	dset, err := f.CreateDatasetWith(dsname, hdf5.T_NATIVE_INT, space, dcpl)
+	if err != nil {
+		panic(err)
+	}
+	defer dset.Close()
+
+	// write the initial 3x3 block
+	data := []int32{
+		1, 1, 1,
+		1, 1, 1,
+		1, 1, 1,
+	}
+	err = dset.Write(&data)
+	if err != nil {
+		panic(err)
+	}
+
+	// extend the dataset to 10x3 and fill the new 7x3 rows
+	err = dset.SetExtent([]uint{10, 3})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf(":: dset extended to 10x3\n")
+
+	filespace := dset.Space()
+	defer filespace.Close()
+	err = filespace.SelectHyperslab([]uint{3, 0}, nil, []uint{7, 3}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	memspace, err := hdf5.CreateSimpleDataspace([]uint{7, 3}, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer memspace.Close()
+
+	data2 := make([]int32, 7*3)
+	for i := range data2 {
+		data2[i] = 2
+	}
+	err = dset.WriteSubset(&data2, memspace, filespace)
+	if err != nil {
+		panic(err)
+	}
+
+	// extend the dataset to 10x5 and fill the new 10x2 columns
+	err = dset.SetExtent([]uint{10, 5})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf(":: dset extended to 10x5\n")
+
+	filespace2 := dset.Space()
+	defer filespace2.Close()
+	err = filespace2.SelectHyperslab([]uint{0, 3}, nil, []uint{10, 2}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	memspace2, err := hdf5.CreateSimpleDataspace([]uint{10, 2}, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer memspace2.Close()
+
+	data3 := make([]int32, 10*2)
+	for i := range data3 {
+		data3[i] = 3
+	}
+	err = dset.WriteSubset(&data3, memspace2, filespace2)
+	if err != nil {
+		panic(err)
+	}
+
+	// read everything back
+	curdims, _, err := dset.Extent()
+	if err != nil {
+		panic(err)
+	}
+	all := make([]int32, curdims[0]*curdims[1])
+	err = dset.Read(&all)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf(":: dset dims: %v\n", curdims)
+	for i := uint(0); i < curdims[0]; i++ {
+		fmt.Printf(":: %v\n", all[i*curdims[1]:(i+1)*curdims[1]])
+	}
 }
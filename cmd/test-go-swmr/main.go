@@ -0,0 +1,194 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/usace/go-hdf5"
+)
+
+const (
+	fname  string = "SDSswmr.h5"
+	dsname string = "Series"
+	ncols  uint   = 4  // values per row
+	nrows  uint   = 20 // rows appended by the writer
+)
+
+func main() {
+	reader := flag.Bool("reader", false, "read the file written by another process")
+	flag.Parse()
+
+	if *reader {
+		read()
+		return
+	}
+	write()
+}
+
+// write creates the file and its dataset, starts a reader process and
+// appends rows to the dataset.
+func write() {
+	// SWMR needs the latest file format
+	fapl, err := hdf5.NewPropList(hdf5.P_FILE_ACCESS)
+	if err != nil {
+		panic(err)
+	}
+	defer fapl.Close()
+	err = fapl.SetLibverBounds(hdf5.F_LIBVER_LATEST, hdf5.F_LIBVER_LATEST)
+	if err != nil {
+		panic(err)
+	}
+
+	f, err := hdf5.CreateFileWith(fname, hdf5.F_ACC_TRUNC, hdf5.P_DEFAULT, fapl)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	fmt.Printf(":: file [%s] created\n", f.Name())
+
+	// the dataset grows along its unlimited first dimension
+	space, err := hdf5.CreateSimpleDataspace([]uint{0, ncols}, []uint{hdf5.S_UNLIMITED, ncols})
+	if err != nil {
+		panic(err)
+	}
+	defer space.Close()
+
+	dcpl, err := hdf5.NewPropList(hdf5.P_DATASET_CREATE)
+	if err != nil {
+		panic(err)
+	}
+	defer dcpl.Close()
+	err = dcpl.SetChunk([]uint{4, ncols})
+	if err != nil {
+		panic(err)
+	}
+
+	dset, err := f.CreateDatasetWith(dsname, hdf5.T_NATIVE_INT32, space, dcpl)
+	if err != nil {
+		panic(err)
+	}
+	defer dset.Close()
+
+	// no object may be created from now on
+	err = f.StartSWMRWrite()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf(":: SWMR write started\n")
+
+	cmd := exec.Command(os.Args[0], "-reader")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Start()
+	if err != nil {
+		panic(err)
+	}
+
+	memspace, err := hdf5.CreateSimpleDataspace([]uint{1, ncols}, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer memspace.Close()
+
+	row := make([]int32, ncols)
+	for i := uint(0); i < nrows; i++ {
+		err = dset.SetExtent([]uint{i + 1, ncols})
+		if err != nil {
+			panic(err)
+		}
+		filespace := dset.Space()
+		err = filespace.SelectHyperslab([]uint{i, 0}, nil, []uint{1, ncols}, nil)
+		if err != nil {
+			panic(err)
+		}
+		for j := range row {
+			row[j] = int32(i*ncols) + int32(j)
+		}
+		err = dset.WriteSubset(&row, memspace, filespace)
+		filespace.Close()
+		if err != nil {
+			panic(err)
+		}
+
+		// make the new row visible to the reader
+		err = dset.Flush()
+		if err != nil {
+			panic(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	fmt.Printf(":: writer appended %d rows\n", nrows)
+
+	err = cmd.Wait()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// read polls the dataset and prints the rows as they are appended.
+func read() {
+	f, err := hdf5.OpenFile(fname, hdf5.F_ACC_RDONLY|hdf5.F_ACC_SWMR_READ)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	dset, err := f.OpenDataset(dsname)
+	if err != nil {
+		panic(err)
+	}
+	defer dset.Close()
+
+	deadline := time.Now().Add(30 * time.Second)
+	var seen uint
+	for seen < nrows {
+		if time.Now().After(deadline) {
+			panic(fmt.Errorf("timeout after reading %d of %d rows", seen, nrows))
+		}
+
+		// pick up the rows flushed by the writer
+		err = dset.Refresh()
+		if err != nil {
+			panic(err)
+		}
+		dims, _, err := dset.Extent()
+		if err != nil {
+			panic(err)
+		}
+		if dims[0] == seen {
+			time.Sleep(20 * time.Millisecond)
+			continue
+		}
+
+		n := dims[0] - seen
+		filespace := dset.Space()
+		err = filespace.SelectHyperslab([]uint{seen, 0}, nil, []uint{n, ncols}, nil)
+		if err != nil {
+			panic(err)
+		}
+		memspace, err := hdf5.CreateSimpleDataspace([]uint{n, ncols}, nil)
+		if err != nil {
+			panic(err)
+		}
+		rows := make([]int32, n*ncols)
+		err = dset.ReadSubset(&rows, memspace, filespace)
+		memspace.Close()
+		filespace.Close()
+		if err != nil {
+			panic(err)
+		}
+
+		for i := uint(0); i < n; i++ {
+			fmt.Printf(":: reader row %d: %v\n", seen+i, rows[i*ncols:(i+1)*ncols])
+		}
+		seen = dims[0]
+	}
+	fmt.Printf(":: reader read %d rows\n", seen)
+}
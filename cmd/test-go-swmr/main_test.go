@@ -0,0 +1,27 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestSWMR(t *testing.T) {
+	const fname = "SDSswmr.h5"
+	stdout := new(bytes.Buffer)
+	cmd := exec.Command("test-go-swmr")
+	cmd.Stdout = stdout
+	cmd.Stderr = stdout
+	cmd.Stdin = os.Stdin
+
+	err := cmd.Run()
+	if err != nil {
+		t.Fatalf("error: %v\n%s\n", err, string(stdout.Bytes()))
+	}
+	os.Remove(fname)
+}
@@ -85,16 +85,9 @@ func (enc *Encoder) Encode(data interface{}) error {
 		return nil
 
 	case reflect.String:
-		str := append([]byte(rv.String()), 0)
-
-		// This direct machine conversion is only used
-		// because HDF5 uses machine endianism.
-		//
-		// DO NOT DO THIS AT HOME.
-		//
-		raw := (*[unsafe.Sizeof(uintptr(0))]byte)(unsafe.Pointer(&str))
-		enc.Buf = append(enc.Buf, raw[:]...)
-		enc.offset += len(raw)
+		// The C representation of a string is a pointer to its bytes,
+		// which may not be passed to C from Go memory.
+		return fmt.Errorf("cmem: cannot encode string in Go memory")
 
 	case reflect.Ptr:
 		return enc.Encode(rv.Elem())
@@ -5,6 +5,8 @@
 package hdf5
 
 // #include "hdf5.h"
+// #include <stdint.h>
+// #include <string.h>
 //
 // herr_t _go_hdf5_unsilence_errors(void) {
 //   return H5Eset_auto2(H5E_DEFAULT, (H5E_auto2_t)(H5Eprint), stderr);
@@ -13,14 +15,185 @@ package hdf5
 // herr_t _go_hdf5_silence_errors(void) {
 //   return H5Eset_auto2(H5E_DEFAULT, NULL, NULL);
 // }
+//
+// extern herr_t _go_hdf5_ewalk_cb(unsigned n, H5E_error2_t *err_desc, void *client_data);
+//
+// static inline herr_t _go_hdf5_H5Ewalk2(hid_t stack_id, uintptr_t data) {
+//   return H5Ewalk2(stack_id, H5E_WALK_DOWNWARD, (H5E_walk2_t)_go_hdf5_ewalk_cb, (void *)data);
+// }
+//
+// enum {
+//   _go_hdf5_ERR_OTHER,
+//   _go_hdf5_ERR_NOTFOUND,
+//   _go_hdf5_ERR_EXISTS,
+//   _go_hdf5_ERR_READONLY,
+//   _go_hdf5_ERR_BADTYPE,
+// };
+//
+// static inline int _go_hdf5_error_kind(hid_t maj, hid_t min, const char *desc) {
+//   if (min == H5E_NOTFOUND) {
+//     return _go_hdf5_ERR_NOTFOUND;
+//   }
+//   if (min == H5E_EXISTS || min == H5E_ALREADYEXISTS) {
+//     return _go_hdf5_ERR_EXISTS;
+//   }
+//   if (min == H5E_WRITEERROR && desc != NULL && strstr(desc, "no write intent") != NULL) {
+//     return _go_hdf5_ERR_READONLY;
+//   }
+//   if (min == H5E_BADTYPE || min == H5E_CANTCONVERT || (maj == H5E_DATATYPE && min == H5E_UNSUPPORTED)) {
+//     return _go_hdf5_ERR_BADTYPE;
+//   }
+//   return _go_hdf5_ERR_OTHER;
+// }
 import "C"
 
 import (
+	"errors"
 	"fmt"
+	"runtime/cgo"
+	"strings"
+)
+
+var (
+	// ErrNotFound matches errors caused by a missing object, link or attribute.
+	ErrNotFound = errors.New("hdf5: not found")
+
+	// ErrExists matches errors caused by creating an object, link or
+	// attribute with a name that is already used.
+	ErrExists = errors.New("hdf5: already exists")
+
+	// ErrReadOnly matches errors caused by writing to a file opened without
+	// write intent.
+	ErrReadOnly = errors.New("hdf5: read-only")
+
+	// ErrBadType matches errors caused by an inappropriate datatype or a
+	// conversion between datatypes that is not possible.
+	ErrBadType = errors.New("hdf5: bad datatype")
 )
 
+// ErrorRecord is an entry of the HDF5 error stack.
+type ErrorRecord struct {
+	Major string // Description of the major error, e.g. "Dataset"
+	Minor string // Description of the minor error, e.g. "Object not found"
+	Func  string // Function of the HDF5 library that pushed the error
+	File  string // Source file of the HDF5 library that pushed the error
+	Line  int    // Line in File
+	Desc  string // Detailed description of the error
+
+	kind error
+}
+
+func (r ErrorRecord) String() string {
+	return fmt.Sprintf("%s:%d in %s(): %s: %s: %s", r.File, r.Line, r.Func, r.Desc, r.Major, r.Minor)
+}
+
+// ErrorStack is the error returned by failing calls into the HDF5 library.
+// It holds the records of the HDF5 error stack at the time of the failure,
+// from the API function that was called down to the innermost cause.
+//
+// ErrorStack can be matched with errors.Is against ErrNotFound, ErrExists,
+// ErrReadOnly and ErrBadType.
+type ErrorStack struct {
+	Code    int // Negative return value of the failing call
+	Records []ErrorRecord
+}
+
+func (e *ErrorStack) Error() string {
+	if len(e.Records) == 0 {
+		return fmt.Sprintf("hdf5: code %d", e.Code)
+	}
+	api, cause := e.Records[0], e.Records[len(e.Records)-1]
+	msg := fmt.Sprintf("hdf5: %s(): %s", api.Func, cause.Desc)
+	if cause.Minor != "" {
+		msg += " (" + strings.ToLower(cause.Minor) + ")"
+	}
+	return msg
+}
+
+// Is reports whether one of the records of the stack matches target.
+func (e *ErrorStack) Is(target error) bool {
+	for _, r := range e.Records {
+		if r.kind != nil && r.kind == target {
+			return true
+		}
+	}
+	return false
+}
+
+// newErrorStack returns an ErrorStack holding the current HDF5 error stack
+// for a call that returned code, and clears the HDF5 error stack. It must be
+// called before any other HDF5 function is called, on the OS thread of the
+// failing call.
+func newErrorStack(code int) *ErrorStack {
+	e := &ErrorStack{Code: code}
+	stack := C.H5Eget_current_stack()
+	if stack < 0 {
+		return e
+	}
+	defer C.H5Eclose_stack(stack)
+
+	h := cgo.NewHandle(&e.Records)
+	defer h.Delete()
+	C._go_hdf5_H5Ewalk2(stack, C.uintptr_t(h))
+	return e
+}
+
+// errorRecord converts an HDF5 error description to an ErrorRecord.
+func errorRecord(desc *C.H5E_error2_t) ErrorRecord {
+	r := ErrorRecord{
+		Major: errorMessage(desc.maj_num),
+		Minor: errorMessage(desc.min_num),
+		Func:  C.GoString(desc.func_name),
+		File:  C.GoString(desc.file_name),
+		Line:  int(desc.line),
+		Desc:  C.GoString(desc.desc),
+	}
+	switch C._go_hdf5_error_kind(desc.maj_num, desc.min_num, desc.desc) {
+	case C._go_hdf5_ERR_NOTFOUND:
+		r.kind = ErrNotFound
+	case C._go_hdf5_ERR_EXISTS:
+		r.kind = ErrExists
+	case C._go_hdf5_ERR_READONLY:
+		r.kind = ErrReadOnly
+	case C._go_hdf5_ERR_BADTYPE:
+		r.kind = ErrBadType
+	}
+	return r
+}
+
+// errorMessage returns the message of an HDF5 major or minor error number.
+func errorMessage(id C.hid_t) string {
+	var buf [256]C.char
+	n := C.H5Eget_msg(id, nil, &buf[0], C.size_t(len(buf)))
+	if n <= 0 {
+		return ""
+	}
+	return C.GoString(&buf[0])
+}
+
+// FilterNotAvailableError is the error returned when reading a dataset whose
+// filter pipeline uses a filter that is neither built into the library nor
+// registered, nor found as a plugin in one of the PluginPaths.
+type FilterNotAvailableError struct {
+	ID   FilterID
+	Name string // Name of the filter recorded in the file, if any
+	Err  error  // Error returned by the read
+}
+
+func (e *FilterNotAvailableError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("hdf5: filter %d not available", int(e.ID))
+	}
+	return fmt.Sprintf("hdf5: filter %d (%s) not available", int(e.ID), e.Name)
+}
+
+func (e *FilterNotAvailableError) Unwrap() error {
+	return e.Err
+}
+
 // DisplayErrors enables/disables HDF5's automatic error printing
 func DisplayErrors(on bool) error {
+	defer lockThread()()
 	var err error
 	if on {
 		err = h5err(C._go_hdf5_unsilence_errors())
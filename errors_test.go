@@ -0,0 +1,92 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestErrorStack(t *testing.T) {
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(fname)
+
+	space, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dset, err := f.CreateDataset("dset", T_NATIVE_INT32, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dset.Close()
+
+	_, err = f.OpenDataset("missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound opening a missing dataset, got %v", err)
+	}
+	for _, sentinel := range []error{ErrExists, ErrReadOnly, ErrBadType} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("unexpected match of %v with %v", err, sentinel)
+		}
+	}
+	var stack *ErrorStack
+	if !errors.As(err, &stack) {
+		t.Fatalf("expected an *ErrorStack, got %T", err)
+	}
+	if stack.Code >= 0 {
+		t.Errorf("expected a negative code, got %d", stack.Code)
+	}
+	if len(stack.Records) == 0 {
+		t.Fatal("expected records on the error stack")
+	}
+	if got, want := stack.Records[0].Func, "H5Dopen2"; got != want {
+		t.Errorf("wrong API function: got %q, want %q", got, want)
+	}
+	for _, r := range stack.Records {
+		if r.Major == "" || r.Minor == "" || r.File == "" || r.Line == 0 {
+			t.Errorf("incomplete record: %+v", r)
+		}
+	}
+
+	_, err = f.CreateDataset("dset", T_NATIVE_INT32, space)
+	if !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists creating a dataset twice, got %v", err)
+	}
+	_, err = f.CreateGroup("dset")
+	if wrapped := fmt.Errorf("wrapped: %w", err); !errors.Is(wrapped, ErrExists) {
+		t.Errorf("expected wrapped ErrExists creating a group over a dataset, got %v", wrapped)
+	}
+
+	// A dataspace identifier is not a datatype.
+	if err = NewDatatype(space.id).SetSize(4); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType using a dataspace as a datatype, got %v", err)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = OpenFile(fname, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dset, err = f.OpenDataset("dset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	err = dset.Write(&[4]int32{1, 2, 3, 4})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly writing to a read-only file, got %v", err)
+	}
+}
@@ -12,19 +12,21 @@ import "C"
 import (
 	"fmt"
 	"reflect"
-	"strings"
 	"unsafe"
 )
 
 type Attribute struct {
 	Identifier
+
+	strOpts StringOptions
 }
 
 func newAttribute(id C.hid_t) *Attribute {
-	return &Attribute{Identifier{id}}
+	return &Attribute{Identifier: Identifier{id}}
 }
 
 func createAttribute(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 	hid := C.H5Acreate2(id, c_name, dtype.id, dspace.id, acpl.id, P_DEFAULT.id)
@@ -35,6 +37,7 @@ func createAttribute(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace
 }
 
 func openAttribute(id C.hid_t, name string) (*Attribute, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -52,6 +55,101 @@ func attributeExists(id C.hid_t, attrname string) bool {
 	return e > 0
 }
 
+// CreateAttribute creates a new attribute at this location. The returned
+// attribute must be closed by the user when it is no longer needed.
+func (l *Location) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttribute(l.id, name, dtype, dspace, P_DEFAULT)
+}
+
+// CreateAttributeWith creates a new attribute at this location with a user-defined
+// PropList. The returned attribute must be closed by the user when it is no longer needed.
+func (l *Location) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	return createAttribute(l.id, name, dtype, dspace, acpl)
+}
+
+// OpenAttribute opens an existing attribute. The returned attribute must be closed
+// by the user when it is no longer needed.
+func (l *Location) OpenAttribute(name string) (*Attribute, error) {
+	return openAttribute(l.id, name)
+}
+
+// AttributeExists returns whether an attribute with the specified name is
+// attached to this location.
+func (l *Location) AttributeExists(name string) bool {
+	return attributeExists(l.id, name)
+}
+
+// NumAttributes returns the number of attributes attached to this location.
+func (l *Location) NumAttributes() (int, error) {
+	info, err := l.Info()
+	if err != nil {
+		return 0, err
+	}
+	return int(info.NumAttrs), nil
+}
+
+// AttributeNames returns the names of the attributes attached to this
+// location, in alphanumeric order.
+func (l *Location) AttributeNames() ([]string, error) {
+	n, err := l.NumAttributes()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, n)
+	for i := range names {
+		names[i], err = attributeNameByIndex(l.id, uint(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+func attributeNameByIndex(id C.hid_t, idx uint) (string, error) {
+	cidx := C.hsize_t(idx)
+	size := C.H5Aget_name_by_idx(id, cdot, C.H5_INDEX_NAME, C.H5_ITER_INC, cidx, nil, 0, C.H5P_DEFAULT)
+	if size < 0 {
+		return "", fmt.Errorf("could not get name of attribute %d", idx)
+	}
+
+	name := make([]C.char, size+1)
+	size = C.H5Aget_name_by_idx(id, cdot, C.H5_INDEX_NAME, C.H5_ITER_INC, cidx, &name[0], C.size_t(size)+1, C.H5P_DEFAULT)
+	if size < 0 {
+		return "", fmt.Errorf("could not get name of attribute %d", idx)
+	}
+	return C.GoString(&name[0]), nil
+}
+
+// OpenAttributeByIndex opens the attribute at position idx in the alphanumeric
+// order of attribute names, as returned by AttributeNames. The returned
+// attribute must be closed by the user when it is no longer needed.
+func (l *Location) OpenAttributeByIndex(idx uint) (*Attribute, error) {
+	defer lockThread()()
+	hid := C.H5Aopen_by_idx(l.id, cdot, C.H5_INDEX_NAME, C.H5_ITER_INC, C.hsize_t(idx), P_DEFAULT.id, P_DEFAULT.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	return newAttribute(hid), nil
+}
+
+// DeleteAttribute removes the attribute with the specified name from this location.
+func (l *Location) DeleteAttribute(name string) error {
+	defer lockThread()()
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+	return h5err(C.H5Adelete(l.id, c_name))
+}
+
+// RenameAttribute changes the name of an attribute attached to this location.
+func (l *Location) RenameAttribute(oldName, newName string) error {
+	defer lockThread()()
+	c_old := C.CString(oldName)
+	defer C.free(unsafe.Pointer(c_old))
+	c_new := C.CString(newName)
+	defer C.free(unsafe.Pointer(c_new))
+	return h5err(C.H5Arename(l.id, c_old, c_new))
+}
+
 // Access the type of an attribute
 func (s *Attribute) GetType() Identifier {
 	ftype := C.H5Aget_type(s.id)
@@ -77,116 +175,179 @@ func (s *Attribute) Space() *Dataspace {
 }
 
 // Read reads raw data from a attribute into a buffer.
+//
+// Values holding strings, slices or pointers are read through a buffer in C
+// memory with the memory datatype derived from their Go type, and dtype is
+// ignored. Both variable-length and fixed-length strings are read into Go
+// strings, such as a string, a []string or a [N]string, see
+// SetStringOptions.
 func (s *Attribute) Read(data interface{}, dtype *Datatype) error {
-	var (
-		addr unsafe.Pointer
-		rv   = reflect.ValueOf(data)
-	)
+	defer lockThread()()
+	rv := reflect.ValueOf(data)
 	if rv.Kind() != reflect.Ptr {
 		return fmt.Errorf("hdf5: read expects a pointer value")
 	}
 	v := reflect.Indirect(rv)
 
-	switch v.Kind() {
-
-	case reflect.Array:
-		addr = unsafe.Pointer(v.UnsafeAddr())
-
-	case reflect.String:
-		dtAttr, err := copyDatatype(s.GetType().id)
-		if err != nil {
-			return fmt.Errorf("hdf5: could not access attribute datatype: %v", err)
-		}
-		defer dtAttr.Close()
-
-		dtype = dtAttr
-		dlen := dtype.Size()
-		//////////// replaced original line commented out below to add a memset
-		////////////   due to hdf5 allocations in memory that was free'ed but not cleared
-		memsize := C.ulong(uint(unsafe.Sizeof(byte(0))) * (dlen + 1))
-		cptr := unsafe.Pointer(C.malloc(memsize))
-		C.memset(cptr, 0, memsize)
-		cstr := (*C.char)(cptr)
-		////////////
-		//cstr := (*C.char)(unsafe.Pointer(C.malloc(C.ulong(uint(unsafe.Sizeof(byte(0))) * (dlen + 1)))))		defer C.free(unsafe.Pointer(cstr))
-		switch {
-		case C.H5Tis_variable_str(dtAttr.Identifier.id) != 0:
-			addr = unsafe.Pointer(&cstr)
-		default:
-			addr = unsafe.Pointer(cstr)
-		}
-		defer func() {
-			v.SetString(C.GoString(cstr))
-		}()
-
-	default:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+	ftype, err := s.datatype()
+	if err != nil {
+		return err
+	}
+	defer ftype.Close()
+	if t := bufferElem(v.Type(), ftype.Class()); hasGoPointers(t) {
+		return s.readStaged(v, t, ftype, s.strOpts)
 	}
 
-	rc := C.H5Aread(s.id, dtype.id, addr)
-	err := h5err(rc)
+	rc := C.H5Aread(s.id, dtype.id, unsafe.Pointer(v.UnsafeAddr()))
+	err = h5err(rc)
 	return err
 }
 
+// ReadFixedStringArray reads a two-dimensional attribute of fixed-length
+// strings, with their leading and trailing white space removed.
 func (s *Attribute) ReadFixedStringArray() ([][]string, error) {
-	//////////
-	at := s.GetType()
-	dt := NewDatatype(at.HID())
-	defer dt.Close()
-	strSize := dt.Size()
-
 	attrSpace := s.Space()
+	if attrSpace == nil {
+		return nil, fmt.Errorf("hdf5: could not access attribute dataspace")
+	}
 	defer attrSpace.Close()
 
-	dims, maxdims, err := attrSpace.SimpleExtentDims()
-	fmt.Println(maxdims)
+	dims, _, err := attrSpace.SimpleExtentDims()
 	if err != nil {
 		return nil, err
 	}
+	if len(dims) != 2 {
+		return nil, fmt.Errorf("hdf5: attribute has %d dimensions, want 2", len(dims))
+	}
 	rows, cols := int(dims[0]), int(dims[1])
 
-	readStr := ""
-	if err := s.Read(&readStr, dt); err != nil {
+	ftype, err := s.datatype()
+	if err != nil {
+		return nil, err
+	}
+	defer ftype.Close()
+	strs := make([]string, rows*cols)
+	opts := s.strOpts
+	opts.TrimSpace = true
+	if err := s.readStaged(reflect.ValueOf(strs), _go_string_t, ftype, opts); err != nil {
 		return nil, err
 	}
-	return parseStringBufferToMatrix(readStr, int(strSize), rows, cols), nil
+	matrix := make([][]string, rows)
+	for i := range matrix {
+		matrix[i] = strs[i*cols : (i+1)*cols]
+	}
+	return matrix, nil
 }
 
 // Write writes raw data from a buffer to an attribute.
+//
+// Values holding strings, slices or pointers are written through a buffer
+// in C memory with the memory datatype derived from their Go type, and
+// dtype is ignored. Go strings are written to both variable-length and
+// fixed-length strings, see SetStringOptions.
 func (s *Attribute) Write(data interface{}, dtype *Datatype) error {
-	var addr unsafe.Pointer
+	defer lockThread()()
 	v := reflect.Indirect(reflect.ValueOf(data))
-	switch v.Kind() {
 
-	case reflect.Array:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+	ftype, err := s.datatype()
+	if err != nil {
+		return err
+	}
+	defer ftype.Close()
+	if t := bufferElem(v.Type(), ftype.Class()); hasGoPointers(t) {
+		if v.Kind() != reflect.Slice && !v.CanAddr() {
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			v = c
+		}
+		return s.writeStaged(v, t, ftype, s.strOpts)
+	}
 
-	case reflect.String:
-		str := C.CString(v.String())
-		defer C.free(unsafe.Pointer(str))
-		addr = unsafe.Pointer(&str)
+	rc := C.H5Awrite(s.id, dtype.id, unsafe.Pointer(v.UnsafeAddr()))
+	err = h5err(rc)
+	return err
+}
 
-	case reflect.Ptr:
-		addr = unsafe.Pointer(v.Pointer())
+// readStaged reads into the buffer v, whose elements of Go type t hold Go
+// pointers, through a buffer in C memory. ftype is the datatype of the
+// attribute and opts the options of the conversion of fixed-length strings.
+func (s *Attribute) readStaged(v reflect.Value, t reflect.Type, ftype *Datatype, opts StringOptions) error {
+	defer lockThread()()
+	addr, n, err := s.stagedElems(v, t)
+	if err != nil || n == 0 {
+		return err
+	}
+	read := func(mtype *Datatype) func(unsafe.Pointer) error {
+		return func(buf unsafe.Pointer) error {
+			return h5err(C.H5Aread(s.id, mtype.id, buf))
+		}
+	}
+	if t.Kind() == reflect.String && isFixedString(ftype) {
+		return readFixedStrings(addr, n, ftype, nil, opts, read(ftype))
+	}
+	mtype, err := memTypeOf(ftype, t, "attribute")
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+	return readStaged(addr, n, t, mtype, nil, read(mtype))
+}
 
-	default:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+// writeStaged writes from the buffer v, whose elements of Go type t hold Go
+// pointers, through a buffer in C memory. ftype is the datatype of the
+// attribute and opts the options of the conversion of fixed-length strings.
+func (s *Attribute) writeStaged(v reflect.Value, t reflect.Type, ftype *Datatype, opts StringOptions) error {
+	defer lockThread()()
+	addr, n, err := s.stagedElems(v, t)
+	if err != nil || n == 0 {
+		return err
+	}
+	write := func(mtype *Datatype) func(unsafe.Pointer) error {
+		return func(buf unsafe.Pointer) error {
+			return h5err(C.H5Awrite(s.id, mtype.id, buf))
+		}
+	}
+	if t.Kind() == reflect.String && isFixedString(ftype) {
+		return writeFixedStrings(addr, n, ftype, opts, write(ftype))
+	}
+	mtype, err := memTypeOf(ftype, t, "attribute")
+	if err != nil {
+		return err
 	}
+	defer mtype.Close()
+	return writeStaged(addr, n, t, write(mtype))
+}
 
-	rc := C.H5Awrite(s.id, dtype.id, addr)
-	err := h5err(rc)
-	return err
+// stagedElems returns the address of the elements of Go type t of the
+// buffer v and the number of elements of the attribute, after checking that
+// v holds enough elements.
+func (s *Attribute) stagedElems(v reflect.Value, t reflect.Type) (unsafe.Pointer, int, error) {
+	space := s.Space()
+	if space == nil {
+		return nil, 0, fmt.Errorf("hdf5: could not access attribute dataspace")
+	}
+	need := space.SimpleExtentNPoints()
+	space.Close()
+	addr, n := bufferElems(v, t)
+	if n < need {
+		return nil, 0, fmt.Errorf("hdf5: buffer too small for attribute: got %d, need %d elements", n, need)
+	}
+	return addr, need, nil
 }
 
-func parseStringBufferToMatrix(buffer string, stringlen int, rows int, cols int) [][]string {
-	matrix := make([][]string, rows)
-	for i := 0; i < rows; i++ {
-		matrixrow := make([]string, cols)
-		for j := 0; j < cols; j++ {
-			position := (i*2 + j) * stringlen
-			matrixrow[j] = strings.TrimSpace(buffer[position : position+stringlen])
-		}
-		matrix[i] = matrixrow
+// datatype returns the datatype of the attribute. The returned datatype must
+// be closed when it is no longer needed.
+func (s *Attribute) datatype() (*Datatype, error) {
+	defer lockThread()()
+	hid := C.H5Aget_type(s.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
 	}
-	return matrix
+	return NewDatatype(hid), nil
+}
+
+// SetStringOptions sets the options of the conversion of the fixed-length
+// strings of the attribute to and from the Go strings read and written.
+func (s *Attribute) SetStringOptions(opts StringOptions) {
+	s.strOpts = opts
 }
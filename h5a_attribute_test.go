@@ -78,3 +78,204 @@ func TestWriteAttribute(t *testing.T) {
 		})
 	}
 }
+
+func TestAttributeManagement(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+	defer os.Remove(fname)
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s\n", err)
+	}
+	defer f.Close()
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s\n", err)
+	}
+	defer scalar.Close()
+
+	g, err := f.CreateGroup("grp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	dset, err := f.CreateDataset("dset", T_NATIVE_USHORT, scalar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+
+	for name, loc := range map[string]*Location{
+		"file":    &f.Location,
+		"group":   &g.Location,
+		"dataset": &dset.Location,
+	} {
+		t.Run(name, func(t *testing.T) {
+			for i, attrName := range []string{"b", "c", "a"} {
+				attr, err := loc.CreateAttribute(attrName, T_NATIVE_INT32, scalar)
+				if err != nil {
+					t.Fatalf("CreateAttribute failed: %v", err)
+				}
+				v := int32(i)
+				if err = attr.Write(&v, T_NATIVE_INT32); err != nil {
+					t.Fatal(err)
+				}
+				attr.Close()
+			}
+
+			if n, err := loc.NumAttributes(); err != nil {
+				t.Fatal(err)
+			} else if n != 3 {
+				t.Errorf("wrong number of attributes: got %d, want 3", n)
+			}
+
+			names, err := loc.AttributeNames()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"a", "b", "c"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("wrong attribute names: got %q, want %q", names, want)
+			}
+
+			attr, err := loc.OpenAttributeByIndex(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v int32
+			if err = attr.Read(&v, T_NATIVE_INT32); err != nil {
+				t.Fatal(err)
+			}
+			attr.Close()
+			if v != 2 {
+				t.Errorf("wrong value for attribute at index 0: got %d, want 2", v)
+			}
+			if _, err = loc.OpenAttributeByIndex(3); err == nil {
+				t.Error("expected an error opening an out of range attribute")
+			}
+
+			if err = loc.RenameAttribute("a", "z"); err != nil {
+				t.Fatal(err)
+			}
+			if loc.AttributeExists("a") || !loc.AttributeExists("z") {
+				t.Error("attribute was not renamed")
+			}
+			if err = loc.DeleteAttribute("b"); err != nil {
+				t.Fatal(err)
+			}
+			if err = loc.DeleteAttribute("b"); err == nil {
+				t.Error("expected an error deleting a missing attribute")
+			}
+
+			names, err = loc.AttributeNames()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"c", "z"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("wrong attribute names: got %q, want %q", names, want)
+			}
+		})
+	}
+}
+
+func TestStringAttributes(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	want := [2][3]string{{"x", "y", "z"}, {" left", "right ", ""}}
+	fixed8 := FixedGoStringDatatype(8)
+	defer fixed8.Close()
+	for _, dtype := range []*Datatype{T_GO_STRING, fixed8} {
+		name := "vlen"
+		if isFixedString(dtype) {
+			name = "fixed"
+		}
+		attr, err := f.CreateAttribute(name, dtype, space)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer attr.Close()
+		if err := attr.Write(&want, dtype); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var got [2][3]string
+		if err := attr.Read(&got, dtype); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s: wrong strings: got %q, want %q", name, got, want)
+		}
+		flat := make([]string, 6)
+		if err := attr.Read(&flat, dtype); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if flat[3] != want[1][0] {
+			t.Errorf("%s: wrong string: got %q, want %q", name, flat[3], want[1][0])
+		}
+		if err := attr.Read(&[5]string{}, dtype); err == nil {
+			t.Errorf("%s: expected an error reading into a short buffer", name)
+		}
+	}
+
+	fixed, err := f.OpenAttribute("fixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fixed.Close()
+	matrix, err := fixed.ReadFixedStringArray()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"x", "y", "z"}, {"left", "right", ""}}; !reflect.DeepEqual(matrix, want) {
+		t.Errorf("wrong string matrix: got %q, want %q", matrix, want)
+	}
+}
+
+func TestVarLenAttribute(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dtype, err := NewDatatypeFromValue([]int32{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	attr, err := f.CreateAttribute("ranges", dtype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer attr.Close()
+
+	want := [2][]int32{{1, 2, 3}, {-1}}
+	if err := attr.Write(&want, dtype); err != nil {
+		t.Fatal(err)
+	}
+	got := make([][]float64, 2)
+	if err := attr.Read(&got, dtype); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{1, 2, 3}, {-1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong sequences: got %v, want %v", got, want)
+	}
+}
@@ -7,9 +7,26 @@ package hdf5
 // #include "hdf5.h"
 // #include <stdlib.h>
 // #include <string.h>
+//
+// // Chunk enumeration is available from HDF5 1.10.5. The Go functions check
+// // _GO_HDF5_HAVE_CHUNK_INFO before calling the shims.
+// #if H5_VERSION_GE(1, 10, 5)
+// #define _GO_HDF5_HAVE_CHUNK_INFO 1
+// static inline herr_t _go_hdf5_H5Dget_num_chunks(hid_t dset_id, hsize_t *nchunks) {
+//   return H5Dget_num_chunks(dset_id, H5S_ALL, nchunks);
+// }
+// static inline herr_t _go_hdf5_H5Dget_chunk_info(hid_t dset_id, hsize_t index, hsize_t *offset, unsigned *filter_mask, haddr_t *addr, hsize_t *size) {
+//   return H5Dget_chunk_info(dset_id, H5S_ALL, index, offset, filter_mask, addr, size);
+// }
+// #else
+// #define _GO_HDF5_HAVE_CHUNK_INFO 0
+// static inline herr_t _go_hdf5_H5Dget_num_chunks(hid_t dset_id, hsize_t *nchunks) { return -1; }
+// static inline herr_t _go_hdf5_H5Dget_chunk_info(hid_t dset_id, hsize_t index, hsize_t *offset, unsigned *filter_mask, haddr_t *addr, hsize_t *size) { return -1; }
+// #endif
 import "C"
 
 import (
+	"errors"
 	"fmt"
 
 	"reflect"
@@ -17,16 +34,18 @@ import (
 )
 
 type Dataset struct {
-	Identifier
+	Location
 
-	typ *Datatype
+	typ     *Datatype
+	strOpts StringOptions
 }
 
 func newDataset(id C.hid_t, typ *Datatype) *Dataset {
-	return &Dataset{Identifier: Identifier{id}, typ: typ}
+	return &Dataset{Location: Location{Identifier{id}}, typ: typ}
 }
 
 func createDataset(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
+	defer lockThread()()
 	dtype, err := dtype.Copy() // For safety
 	if err != nil {
 		return nil, err
@@ -58,37 +77,118 @@ func (s *Dataset) Space() *Dataspace {
 	return nil
 }
 
-// ReadSubset reads a subset of raw data from a dataset into a buffer.
-func (s *Dataset) ReadSubset(data interface{}, memspace, filespace *Dataspace) error {
-	dtype, err := s.Datatype()
-	defer dtype.Close()
-	if err != nil {
-		return err
+// SetExtent changes the current dimensions of a dataset. The dataset must be
+// chunked, dims must have the rank of the dataset and no dimension may exceed
+// the maximum dimensions of its dataspace.
+func (s *Dataset) SetExtent(dims []uint) error {
+	defer lockThread()()
+	space := s.Space()
+	if space == nil {
+		return fmt.Errorf("couldn't open Dataspace from Dataset %q", s.Name())
+	}
+	rank := space.SimpleExtentNDims()
+	space.Close()
+	if rank <= 0 {
+		return fmt.Errorf("dataset %q does not have a simple dataspace", s.Name())
+	}
+	if len(dims) != rank {
+		return fmt.Errorf("size of dims (%d) does not match rank of dataset (%d)", len(dims), rank)
 	}
 
-	var addr unsafe.Pointer
-	v := reflect.Indirect(reflect.ValueOf(data))
+	c_dims := make([]C.hsize_t, rank)
+	for i := range dims {
+		c_dims[i] = C.hsize_t(dims[i])
+	}
+	return h5err(C.H5Dset_extent(s.id, &c_dims[0]))
+}
 
-	switch v.Kind() {
+// Extent returns the current and maximum dimensions of a dataset.
+// Unlimited dimensions are reported as S_UNLIMITED.
+func (s *Dataset) Extent() (dims, maxdims []uint, err error) {
+	space := s.Space()
+	if space == nil {
+		return nil, nil, fmt.Errorf("couldn't open Dataspace from Dataset %q", s.Name())
+	}
+	defer space.Close()
+	return space.SimpleExtentDims()
+}
 
-	case reflect.Array:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+// Flush writes all the buffers of the dataset to disk. In SWMR mode it makes
+// the data written so far, and the current extent, visible to the readers.
+func (s *Dataset) Flush() error {
+	defer lockThread()()
+	return h5err(C.H5Dflush(s.id))
+}
 
-	case reflect.Slice:
-		slice := (*reflect.SliceHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		addr = unsafe.Pointer(slice.Data)
+// Refresh drops the cached metadata of the dataset and reloads it from disk.
+// In SWMR mode readers call it to see the data flushed by the writer since
+// the dataset was opened or last refreshed, including changes to its extent.
+func (s *Dataset) Refresh() error {
+	defer lockThread()()
+	return h5err(C.H5Drefresh(s.id))
+}
 
-	case reflect.String:
-		str := (*reflect.StringHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		addr = unsafe.Pointer(str.Data)
+// ReadSubset reads a subset of raw data from a dataset into a buffer. The
+// memory datatype is derived from the Go type of the elements of data and
+// the library converts the elements of the dataset to it, so that, for
+// example, a dataset of big-endian 32-bit floats is read into a []float64.
+// See ReadAs to choose the memory datatype.
+//
+// Elements holding strings, slices or pointers are read through a buffer in
+// C memory and copied into Go values, so that data must hold at least as
+// many elements as are read. Both variable-length and fixed-length strings
+// are read into Go strings, see SetStringOptions.
+func (s *Dataset) ReadSubset(data interface{}, memspace, filespace *Dataspace) error {
+	t, err := s.elemType(data)
+	if err != nil {
+		return err
+	}
+	if hasGoPointers(t) {
+		return s.readStaged(data, t, nil, memspace, filespace)
+	}
+	mtype, err := s.memType(t)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+	return s.read(bufferAddr(data), mtype, memspace, filespace)
+}
 
-	case reflect.Ptr:
-		addr = unsafe.Pointer(v.Pointer())
+// Read reads raw data from a dataset into a buffer.
+func (s *Dataset) Read(data interface{}) error {
+	return s.ReadSubset(data, nil, nil)
+}
 
-	default:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+// ReadAs reads raw data from a dataset into a buffer whose elements have the
+// memory datatype memType. The library converts the elements of the dataset
+// to memType. Elements holding strings, slices or pointers are read through
+// a buffer in C memory, whose elements must have the memory datatype. The
+// buffer must hold all elements of the dataset. If memType is nil, ReadAs
+// is the same as Read.
+func (s *Dataset) ReadAs(data interface{}, memType *Datatype) error {
+	if memType == nil {
+		return s.Read(data)
+	}
+	t, err := s.elemType(data)
+	if err != nil {
+		return err
+	}
+	if hasGoPointers(t) {
+		return s.readStaged(data, t, memType, nil, nil)
+	}
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice && !v.CanAddr() {
+		return fmt.Errorf("hdf5: cannot read into a non-pointer %T", data)
+	}
+	addr, n, err := s.bufferAs(v, t, memType)
+	if err != nil || n == 0 {
+		return err
 	}
+	return s.read(addr, memType, nil, nil)
+}
 
+func (s *Dataset) read(buf unsafe.Pointer, mtype *Datatype, memspace, filespace *Dataspace) error {
+	defer lockThread()()
 	var filespace_id, memspace_id C.hid_t = 0, 0
 	if memspace != nil {
 		memspace_id = memspace.id
@@ -96,80 +196,450 @@ func (s *Dataset) ReadSubset(data interface{}, memspace, filespace *Dataspace) e
 	if filespace != nil {
 		filespace_id = filespace.id
 	}
-	rc := C.H5Dread(s.id, dtype.id, memspace_id, filespace_id, 0, addr)
-	err = h5err(rc)
-	return err
+	rc := C.H5Dread(s.id, mtype.id, memspace_id, filespace_id, 0, buf)
+	err := h5err(rc)
+	return s.filterError(err)
 }
 
-// Read reads raw data from a dataset into a buffer.
-func (s *Dataset) Read(data interface{}) error {
-	return s.ReadSubset(data, nil, nil)
+// ReadEnum reads an enumeration dataset into data, which must be a pointer
+// to a slice or array of a Go integer type, such as a user-defined type for
+// named constants. The values are converted by member name, so the Go values
+// of the members may be given with a different base type than in the file.
+func (s *Dataset) ReadEnum(data interface{}) error {
+	defer lockThread()()
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("hdf5: ReadEnum needs a pointer to a slice or array, got %T", data)
+	}
+	if v.Kind() == reflect.Array && !v.CanAddr() {
+		return fmt.Errorf("hdf5: cannot read into a non-pointer %T", data)
+	}
+	switch v.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return fmt.Errorf("hdf5: ReadEnum needs integer elements, got %T", data)
+	}
+
+	dtype, err := s.Datatype()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+	if dtype.Class() != T_ENUM {
+		return fmt.Errorf("hdf5: dataset %q is not an enumeration, has class %v", s.Name(), dtype.Class())
+	}
+
+	space := s.Space()
+	if space == nil {
+		return fmt.Errorf("couldn't open Dataspace from Dataset %q", s.Name())
+	}
+	n := space.SimpleExtentNPoints()
+	space.Close()
+	if v.Len() < n {
+		return fmt.Errorf("hdf5: buffer too small for dataset %q: got %d, need %d elements", s.Name(), v.Len(), n)
+	}
+
+	base, err := NewDataTypeFromType(v.Type().Elem())
+	if err != nil {
+		return err
+	}
+	defer base.Close()
+	mtype, err := (&EnumType{*dtype}).memType(base)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+
+	if n == 0 {
+		return nil
+	}
+	addr := unsafe.Pointer(v.Index(0).UnsafeAddr())
+	return s.filterError(h5err(C.H5Dread(s.id, mtype.id, 0, 0, 0, addr)))
 }
 
-// WriteSubset writes a subset of raw data from a buffer to a dataset.
-func (s *Dataset) WriteSubset(data interface{}, memspace, filespace *Dataspace) error {
+// ReadEnumNames reads an enumeration dataset and returns the member name of
+// each of its elements.
+func (s *Dataset) ReadEnumNames() ([]string, error) {
 	dtype, err := s.Datatype()
+	if err != nil {
+		return nil, err
+	}
 	defer dtype.Close()
+
+	space := s.Space()
+	if space == nil {
+		return nil, fmt.Errorf("couldn't open Dataspace from Dataset %q", s.Name())
+	}
+	values := make([]int64, space.SimpleExtentNPoints())
+	space.Close()
+	if err := s.ReadEnum(&values); err != nil {
+		return nil, err
+	}
+
+	enum := &EnumType{*dtype}
+	names := make(map[int64]string, enum.NMembers())
+	for i, n := 0, enum.NMembers(); i < n; i++ {
+		v, err := enum.MemberValue(i)
+		if err != nil {
+			return nil, err
+		}
+		names[v] = enum.MemberName(i)
+	}
+
+	out := make([]string, len(values))
+	for i, v := range values {
+		name, ok := names[v]
+		if !ok {
+			return nil, fmt.Errorf("hdf5: no enumeration member with value %d in dataset %q", v, s.Name())
+		}
+		out[i] = name
+	}
+	return out, nil
+}
+
+// WriteSubset writes a subset of raw data from a buffer to a dataset. The
+// memory datatype is derived from the Go type of the elements of data and
+// the library converts them to the datatype of the dataset. See WriteAs to
+// choose the memory datatype.
+//
+// Elements holding strings, slices or pointers are copied into a buffer in C
+// memory before they are written.
+func (s *Dataset) WriteSubset(data interface{}, memspace, filespace *Dataspace) error {
+	t, err := s.elemType(data)
 	if err != nil {
 		return err
 	}
+	if hasGoPointers(t) {
+		return s.writeStaged(data, t, nil, memspace, filespace)
+	}
+	mtype, err := s.memType(t)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+	return s.write(bufferAddr(data), mtype, memspace, filespace)
+}
 
-	addr := unsafe.Pointer(nil)
+// Write writes raw data from a buffer to a dataset.
+func (s *Dataset) Write(data interface{}) error {
+	return s.WriteSubset(data, nil, nil)
+}
+
+// WriteAs writes raw data from a buffer whose elements have the memory
+// datatype memType to a dataset. The library converts them to the datatype
+// of the dataset. Elements holding strings, slices or pointers are written
+// through a buffer in C memory, whose elements must have the memory datatype.
+// The buffer must hold all elements of the dataset. If memType is nil,
+// WriteAs is the same as Write.
+func (s *Dataset) WriteAs(data interface{}, memType *Datatype) error {
+	if memType == nil {
+		return s.Write(data)
+	}
+	t, err := s.elemType(data)
+	if err != nil {
+		return err
+	}
+	if hasGoPointers(t) {
+		return s.writeStaged(data, t, memType, nil, nil)
+	}
 	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice && !v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+	addr, n, err := s.bufferAs(v, t, memType)
+	if err != nil || n == 0 {
+		return err
+	}
+	return s.write(addr, memType, nil, nil)
+}
+
+// bufferAs returns the address and number of the elements of Go type t of
+// the buffer v accessed by ReadAs and WriteAs, after checking that they have
+// the size of the memory datatype memType and that v holds all elements of
+// the dataset.
+func (s *Dataset) bufferAs(v reflect.Value, t reflect.Type, memType *Datatype) (unsafe.Pointer, int, error) {
+	if memType.Size() != uint(t.Size()) {
+		return nil, 0, fmt.Errorf("hdf5: memory datatype of size %d does not match %v of size %d: %w", memType.Size(), t, t.Size(), ErrBadType)
+	}
+	return s.stagedElems(v, t, nil, nil)
+}
 
+func (s *Dataset) write(buf unsafe.Pointer, mtype *Datatype, memspace, filespace *Dataspace) error {
+	defer lockThread()()
+	var filespace_id, memspace_id C.hid_t = 0, 0
+	if memspace != nil {
+		memspace_id = memspace.id
+	}
+	if filespace != nil {
+		filespace_id = filespace.id
+	}
+	rc := C.H5Dwrite(s.id, mtype.id, memspace_id, filespace_id, 0, buf)
+	err := h5err(rc)
+	return err
+}
+
+// bufferAddr returns the address of the first element of the buffer data.
+func bufferAddr(data interface{}) unsafe.Pointer {
+	v := reflect.Indirect(reflect.ValueOf(data))
 	switch v.Kind() {
 
 	case reflect.Array:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+		return unsafe.Pointer(v.UnsafeAddr())
 
 	case reflect.Slice:
 		slice := (*reflect.SliceHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		addr = unsafe.Pointer(slice.Data)
+		return unsafe.Pointer(slice.Data)
 
 	case reflect.String:
 		str := (*reflect.StringHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		addr = unsafe.Pointer(str.Data)
+		return unsafe.Pointer(str.Data)
 
 	case reflect.Ptr:
-		addr = unsafe.Pointer(v.Pointer())
+		return unsafe.Pointer(v.Pointer())
 
 	default:
-		addr = unsafe.Pointer(v.UnsafeAddr())
+		return unsafe.Pointer(v.UnsafeAddr())
 	}
+}
 
-	var filespace_id, memspace_id C.hid_t = 0, 0
-	if memspace != nil {
-		memspace_id = memspace.id
+// elemType returns the Go type of the elements of the buffer data. Bytes
+// holding opaque data, bitfields or fixed-length strings are grouped into
+// arrays of the size of the elements of the dataset.
+func (s *Dataset) elemType(data interface{}) (reflect.Type, error) {
+	dtype, err := s.Datatype()
+	if err != nil {
+		return nil, err
 	}
-	if filespace != nil {
-		filespace_id = filespace.id
+	defer dtype.Close()
+	t := bufferElem(reflect.Indirect(reflect.ValueOf(data)).Type(), dtype.Class())
+	var raw bool
+	switch dtype.Class() {
+	case T_OPAQUE, T_BITFIELD:
+		raw = true
+	case T_STRING:
+		raw = isFixedString(dtype)
 	}
-	rc := C.H5Dwrite(s.id, dtype.id, memspace_id, filespace_id, 0, addr)
-	err = h5err(rc)
-	return err
+	if raw && isFixedSize(t) && t.Size() == 1 && dtype.Size() > 1 {
+		t = reflect.ArrayOf(int(dtype.Size()), t)
+	}
+	return t, nil
 }
 
-// Write writes raw data from a buffer to a dataset.
-func (s *Dataset) Write(data interface{}) error {
-	return s.WriteSubset(data, nil, nil)
+// memType returns the memory datatype of buffer elements of Go type t. It is
+// derived from t when the dataset holds numbers, enumerations or references,
+// or compounds whose members match fields of t by name and convert to them.
+// Otherwise it is the datatype of the dataset and the elements must have its
+// size, as when reading opaque data into bytes or compounds into structs
+// with the names and layout of their members.
+func (s *Dataset) memType(t reflect.Type) (*Datatype, error) {
+	dtype, err := s.Datatype()
+	if err != nil {
+		return nil, err
+	}
+	if isFixedSize(t) {
+		switch dtype.Class() {
+		case T_INTEGER, T_FLOAT, T_ENUM, T_REFERENCE:
+			dtype.Close()
+			return s.memTypeOf(t)
+		case T_COMPOUND:
+			mtype, err := s.memTypeOf(t)
+			if err == nil || !sameLayout(&CompoundType{*dtype}, t) {
+				dtype.Close()
+				return mtype, err
+			}
+		}
+	}
+	if dtype.Size() != uint(t.Size()) {
+		defer dtype.Close()
+		return nil, fmt.Errorf("hdf5: elements of dataset %q of size %d cannot be copied to and from %v of size %d: %w", s.Name(), dtype.Size(), t, t.Size(), ErrBadType)
+	}
+	return dtype, nil
 }
 
-// Creates a new attribute at this location. The returned attribute
-// must be closed by the user when it is no longer needed.
-func (s *Dataset) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
-	return createAttribute(s.id, name, dtype, dspace, P_DEFAULT)
+// sameLayout returns whether the fields of the struct type t have the names,
+// offsets and sizes of the members of ctype, in the same order.
+func sameLayout(ctype *CompoundType, t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != ctype.NMembers() {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := string(f.Tag)
+		if len(name) == 0 {
+			name = f.Name
+		}
+		if ctype.MemberName(i) != name || ctype.MemberOffset(i) != int(f.Offset) {
+			return false
+		}
+		mtype, err := ctype.MemberType(i)
+		if err != nil {
+			return false
+		}
+		size := mtype.Size()
+		mtype.Close()
+		if size != uint(f.Type.Size()) {
+			return false
+		}
+	}
+	return true
 }
 
-// Creates a new attribute at this location. The returned
-// attribute must be closed by the user when it is no longer needed.
-func (s *Dataset) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
-	return createAttribute(s.id, name, dtype, dspace, acpl)
+// readStaged reads into the buffer data, whose elements of Go type t hold Go
+// pointers, through a buffer in C memory with the memory datatype memType,
+// or one derived from t if memType is nil. Fixed-length strings are
+// converted with the string options of the dataset.
+func (s *Dataset) readStaged(data interface{}, t reflect.Type, memType *Datatype, memspace, filespace *Dataspace) error {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice && !v.CanAddr() {
+		return fmt.Errorf("hdf5: cannot read into a non-pointer %T", data)
+	}
+	addr, n, err := s.stagedElems(v, t, memspace, filespace)
+	if err != nil || n == 0 {
+		return err
+	}
+	mtype, fixed, err := s.stagedMemType(t, memType)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+	read := func(buf unsafe.Pointer) error {
+		return s.read(buf, mtype, memspace, filespace)
+	}
+	if fixed {
+		return readFixedStrings(addr, n, mtype, memspace, s.strOpts, read)
+	}
+	return readStaged(addr, n, t, mtype, memspace, read)
 }
 
-// Opens an existing attribute. The returned attribute must be closed
-// by the user when it is no longer needed.
-func (s *Dataset) OpenAttribute(name string) (*Attribute, error) {
-	return openAttribute(s.id, name)
+// writeStaged writes from the buffer data, whose elements of Go type t hold
+// Go pointers, through a buffer in C memory with the memory datatype memType,
+// or one derived from t if memType is nil. Fixed-length strings are
+// converted with the string options of the dataset.
+func (s *Dataset) writeStaged(data interface{}, t reflect.Type, memType *Datatype, memspace, filespace *Dataspace) error {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice && !v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+	addr, n, err := s.stagedElems(v, t, memspace, filespace)
+	if err != nil || n == 0 {
+		return err
+	}
+	mtype, fixed, err := s.stagedMemType(t, memType)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+	write := func(buf unsafe.Pointer) error {
+		return s.write(buf, mtype, memspace, filespace)
+	}
+	if fixed {
+		return writeFixedStrings(addr, n, mtype, s.strOpts, write)
+	}
+	return writeStaged(addr, n, t, write)
+}
+
+// stagedMemType returns the memory datatype of staged elements of Go type t,
+// and whether they are Go strings transferred as fixed-length strings. It is
+// a copy of memType if memType is not nil, after checking that it has the
+// size of the elements in C memory. Otherwise it is the datatype of the
+// dataset for fixed-length strings, or one derived from t.
+func (s *Dataset) stagedMemType(t reflect.Type, memType *Datatype) (*Datatype, bool, error) {
+	if memType == nil {
+		dtype, err := s.Datatype()
+		if err != nil {
+			return nil, false, err
+		}
+		if t.Kind() == reflect.String && isFixedString(dtype) {
+			return dtype, true, nil
+		}
+		dtype.Close()
+		mtype, err := s.memTypeOf(t)
+		return mtype, false, err
+	}
+	if t.Kind() == reflect.String && isFixedString(memType) {
+		mtype, err := memType.Copy()
+		return mtype, true, err
+	}
+	layout, err := newMemLayout(t)
+	if err != nil {
+		return nil, false, err
+	}
+	if uintptr(memType.Size()) != layout.size {
+		return nil, false, fmt.Errorf("hdf5: memory datatype of size %d does not match %v of size %d in C memory: %w", memType.Size(), t, layout.size, ErrBadType)
+	}
+	mtype, err := memType.Copy()
+	return mtype, false, err
+}
+
+// stagedElems returns the address of the elements of Go type t of the
+// buffer v and the number of them accessed by a transfer between memspace
+// and filespace, after checking that v holds enough elements.
+func (s *Dataset) stagedElems(v reflect.Value, t reflect.Type, memspace, filespace *Dataspace) (unsafe.Pointer, int, error) {
+	addr, n := bufferElems(v, t)
+	need, err := s.bufferLen(memspace, filespace)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n < need {
+		return nil, 0, fmt.Errorf("hdf5: buffer too small for dataset %q: got %d, need %d elements", s.Name(), n, need)
+	}
+	return addr, need, nil
+}
+
+// SetStringOptions sets the options of the conversion of the fixed-length
+// strings of the dataset to and from the Go strings read and written.
+func (s *Dataset) SetStringOptions(opts StringOptions) {
+	s.strOpts = opts
+}
+
+// bufferLen returns the number of elements of a buffer accessed by a
+// transfer between memspace and filespace, either of which may be nil.
+func (s *Dataset) bufferLen(memspace, filespace *Dataspace) (int, error) {
+	switch {
+	case memspace != nil:
+		return memspace.SimpleExtentNPoints(), nil
+	case filespace != nil:
+		return filespace.SelectionNPoints()
+	}
+	space := s.Space()
+	if space == nil {
+		return 0, fmt.Errorf("couldn't open Dataspace from Dataset %q", s.Name())
+	}
+	defer space.Close()
+	return space.SimpleExtentNPoints(), nil
+}
+
+// memTypeOf returns the memory datatype of the Go type t after checking that
+// the elements of the dataset may be converted to and from it.
+func (s *Dataset) memTypeOf(t reflect.Type) (*Datatype, error) {
+	dtype, err := s.Datatype()
+	if err != nil {
+		return nil, err
+	}
+	defer dtype.Close()
+	return memTypeOf(dtype, t, fmt.Sprintf("dataset %q", s.Name()))
+}
+
+// bufferElem returns the Go type of the elements of a buffer of type t for a
+// dataset of the given class. Nested arrays are flattened, such as
+// [rows][cols]float64 for a two-dimensional dataset, unless the elements of
+// the dataset are arrays themselves.
+func bufferElem(t reflect.Type, class TypeClass) reflect.Type {
+	if t.Kind() == reflect.Slice || (t.Kind() == reflect.Array && class == T_ARRAY) {
+		t = t.Elem()
+	}
+	if class == T_ARRAY {
+		return t
+	}
+	for t.Kind() == reflect.Array && t != _go_region_reference_t {
+		t = t.Elem()
+	}
+	return t
 }
 
 // Datatype returns the HDF5 Datatype of the Dataset. The returned
@@ -182,6 +652,206 @@ func (s *Dataset) Datatype() (*Datatype, error) {
 	return NewDatatype(dtype_id), nil
 }
 
+// CreatePropList returns a copy of the dataset creation property list of the
+// Dataset, which holds its chunking and filter settings. The returned
+// property list must be closed by the user when it is no longer needed.
+func (s *Dataset) CreatePropList() (*PropList, error) {
+	defer lockThread()()
+	hid := C.H5Dget_create_plist(s.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	return newPropList(hid), nil
+}
+
+// StorageSize returns the number of bytes allocated in the file for the
+// raw data of the dataset, after filters. It returns 0 if no space is
+// allocated yet.
+func (s *Dataset) StorageSize() uint {
+	return uint(C.H5Dget_storage_size(s.id))
+}
+
+// Offset returns the address in the file of the raw data of the dataset.
+// It returns an error unless the dataset has a contiguous layout and its
+// space is allocated.
+func (s *Dataset) Offset() (uint, error) {
+	addr := C.H5Dget_offset(s.id)
+	if addr == C.HADDR_UNDEF {
+		return 0, fmt.Errorf("hdf5: dataset %q has no contiguous storage allocated", s.Name())
+	}
+	return uint(addr), nil
+}
+
+// SpaceStatus returns whether the storage space of the dataset is allocated.
+func (s *Dataset) SpaceStatus() (SpaceStatus, error) {
+	defer lockThread()()
+	var status C.H5D_space_status_t
+	err := h5err(C.H5Dget_space_status(s.id, &status))
+	return SpaceStatus(status), err
+}
+
+// ChunkInfo describes a chunk of a chunked dataset as it is stored in the
+// file.
+type ChunkInfo struct {
+	Offset     []uint // Logical position of the first element of the chunk in the dataset
+	FilterMask uint   // Bit i set when filter i of the pipeline was skipped for the chunk
+	Addr       uint   // Address of the chunk in the file
+	Size       uint   // Number of bytes of the chunk in the file, after filters
+}
+
+// NumChunks returns the number of chunks of the dataset that are allocated
+// in the file. It requires HDF5 1.10.5 or later.
+func (s *Dataset) NumChunks() (int, error) {
+	defer lockThread()()
+	if C._GO_HDF5_HAVE_CHUNK_INFO == 0 {
+		return 0, errNoChunkInfo
+	}
+	var n C.hsize_t
+	if err := h5err(C._go_hdf5_H5Dget_num_chunks(s.id, &n)); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// errNoChunkInfo is returned by NumChunks and ChunkInfo when the library
+// was built from a release of HDF5 that does not enumerate chunks.
+var errNoChunkInfo = errors.New("hdf5: enumerating chunks requires HDF5 1.10.5 or later")
+
+// ChunkInfo returns the description of the allocated chunk at index i, in
+// [0, NumChunks). It requires HDF5 1.10.5 or later.
+func (s *Dataset) ChunkInfo(i int) (ChunkInfo, error) {
+	defer lockThread()()
+	var (
+		info   ChunkInfo
+		c_mask C.uint
+		c_addr C.haddr_t
+		c_size C.hsize_t
+	)
+	if C._GO_HDF5_HAVE_CHUNK_INFO == 0 {
+		return info, errNoChunkInfo
+	}
+	rank, err := s.rank()
+	if err != nil {
+		return info, err
+	}
+	c_offset := make([]C.hsize_t, rank)
+	if err := h5err(C._go_hdf5_H5Dget_chunk_info(s.id, C.hsize_t(i), &c_offset[0], &c_mask, &c_addr, &c_size)); err != nil {
+		return info, fmt.Errorf("hdf5: could not get chunk %d of dataset %q: %w", i, s.Name(), err)
+	}
+	info.Offset = make([]uint, rank)
+	for j := range info.Offset {
+		info.Offset[j] = uint(c_offset[j])
+	}
+	info.FilterMask = uint(c_mask)
+	info.Addr = uint(c_addr)
+	info.Size = uint(c_size)
+	return info, nil
+}
+
+// ReadChunk reads the chunk at offset as it is stored in the file, without
+// going through the filter pipeline. The offset is the logical position of
+// the first element of the chunk and must be a multiple of the chunk
+// dimensions. The returned filter mask tells which filters of the pipeline
+// were skipped when the chunk was written, see ChunkInfo.
+func (s *Dataset) ReadChunk(offset []uint) ([]byte, uint, error) {
+	defer lockThread()()
+	c_offset, err := s.chunkOffset(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	var size C.hsize_t
+	if err := h5err(C.H5Dget_chunk_storage_size(s.id, &c_offset[0], &size)); err != nil {
+		return nil, 0, fmt.Errorf("hdf5: could not get size of chunk %v of dataset %q: %w", offset, s.Name(), err)
+	}
+	if size == 0 {
+		return nil, 0, fmt.Errorf("hdf5: chunk %v of dataset %q is not allocated", offset, s.Name())
+	}
+	data := make([]byte, int(size))
+	var c_mask C.uint32_t
+	if err := h5err(C.H5Dread_chunk(s.id, P_DEFAULT.id, &c_offset[0], &c_mask, unsafe.Pointer(&data[0]))); err != nil {
+		return nil, 0, fmt.Errorf("hdf5: could not read chunk %v of dataset %q: %w", offset, s.Name(), err)
+	}
+	return data, uint(c_mask), nil
+}
+
+// WriteChunk writes data as the chunk at offset, without going through the
+// filter pipeline. The data must be stored as the filters of the pipeline
+// not set in filterMask would have, for example when copied with ReadChunk
+// from a dataset with the same datatype, chunk dimensions and pipeline.
+func (s *Dataset) WriteChunk(offset []uint, filterMask uint, data []byte) error {
+	defer lockThread()()
+	c_offset, err := s.chunkOffset(offset)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("hdf5: empty chunk %v for dataset %q", offset, s.Name())
+	}
+	if err := h5err(C.H5Dwrite_chunk(s.id, P_DEFAULT.id, C.uint32_t(filterMask), &c_offset[0], C.size_t(len(data)), unsafe.Pointer(&data[0]))); err != nil {
+		return fmt.Errorf("hdf5: could not write chunk %v of dataset %q: %w", offset, s.Name(), err)
+	}
+	return nil
+}
+
+// rank returns the rank of the dataspace of the dataset.
+func (s *Dataset) rank() (int, error) {
+	space := s.Space()
+	if space == nil {
+		return 0, fmt.Errorf("couldn't open Dataspace from Dataset %q", s.Name())
+	}
+	defer space.Close()
+	rank := space.SimpleExtentNDims()
+	if rank <= 0 {
+		return 0, fmt.Errorf("dataset %q does not have a simple dataspace", s.Name())
+	}
+	return rank, nil
+}
+
+// chunkOffset returns offset as a C array after checking it has the rank of
+// the dataset.
+func (s *Dataset) chunkOffset(offset []uint) ([]C.hsize_t, error) {
+	rank, err := s.rank()
+	if err != nil {
+		return nil, err
+	}
+	if len(offset) != rank {
+		return nil, fmt.Errorf("size of offset (%d) does not match rank of dataset (%d)", len(offset), rank)
+	}
+	c_offset := make([]C.hsize_t, rank)
+	for i, o := range offset {
+		c_offset[i] = C.hsize_t(o)
+	}
+	return c_offset, nil
+}
+
+// filterError returns a *FilterNotAvailableError wrapping the error err of
+// a read if the filter pipeline of the Dataset uses a filter that is not
+// available, and err otherwise.
+func (s *Dataset) filterError(err error) error {
+	if err == nil {
+		return nil
+	}
+	dcpl, perr := s.CreatePropList()
+	if perr != nil {
+		return err
+	}
+	defer dcpl.Close()
+	n, perr := dcpl.NFilters()
+	if perr != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		info, perr := dcpl.Filter(i)
+		if perr != nil {
+			continue
+		}
+		if ok, _ := FilterAvailable(info.ID); !ok {
+			return &FilterNotAvailableError{ID: info.ID, Name: info.Name, Err: err}
+		}
+	}
+	return err
+}
+
 // hasIllegalGoPointer returns whether the Dataset is known to have
 // a Go pointer to Go pointer chain. If the Dataset was created by
 // a call to OpenDataset without a read operation, it will be false,
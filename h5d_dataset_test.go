@@ -5,6 +5,11 @@
 package hdf5
 
 import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
 	"os"
 	"reflect"
 	"testing"
@@ -205,3 +210,706 @@ func TestSelectHyperslab(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSetExtent(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+	defer os.Remove(fname)
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s\n", err)
+	}
+	defer f.Close()
+
+	dims := []uint{2, 3}
+	dspace, err := CreateSimpleDataspace(dims, []uint{S_UNLIMITED, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dspace.Close()
+
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err = dcpl.SetChunk([]uint{2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	dset, err := f.CreateDatasetWith("dset", T_NATIVE_USHORT, dspace, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+
+	data := []uint16{1, 2, 3, 4, 5, 6}
+	if err = dset.Write(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = dset.SetExtent([]uint{4}); err == nil {
+		t.Error("expected an error for mismatched rank")
+	}
+	if err = dset.SetExtent([]uint{4, 4}); err == nil {
+		t.Error("expected an error when exceeding maxdims")
+	}
+	if err = dset.SetExtent([]uint{4, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	gotDims, gotMaxdims, err := dset.Extent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint{4, 3}; !reflect.DeepEqual(gotDims, want) {
+		t.Errorf("wrong dims: got %v, want %v", gotDims, want)
+	}
+	if want := []uint{S_UNLIMITED, 3}; !reflect.DeepEqual(gotMaxdims, want) {
+		t.Errorf("wrong maxdims: got %v, want %v", gotMaxdims, want)
+	}
+
+	filespace := dset.Space()
+	defer filespace.Close()
+	if err = filespace.SelectHyperslab([]uint{2, 0}, nil, []uint{2, 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	memspace, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memspace.Close()
+
+	more := []uint16{7, 8, 9, 10, 11, 12}
+	if err = dset.WriteSubset(&more, memspace, filespace); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]uint16, 12)
+	if err = dset.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []uint16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong data after extent change: got %v, want %v", got, want)
+	}
+}
+
+func TestDirectChunk(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{4, 6}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err = dcpl.SetChunk([]uint{2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = dcpl.SetDeflate(DefaultCompression); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := f.CreateDatasetWith("src", T_NATIVE_INT32, dspace, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	data := make([]int32, 4*6)
+	for i := range data {
+		data[i] = int32(i * i)
+	}
+	if err = src.Write(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := src.NumChunks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Fatalf("wrong number of chunks: got %d, want 4", n)
+	}
+	if _, err := src.ChunkInfo(n); err == nil {
+		t.Error("expected an error for a chunk index out of range")
+	}
+	if _, _, err := src.ReadChunk([]uint{0}); err == nil {
+		t.Error("expected an error for mismatched rank")
+	}
+
+	dst, err := f.CreateDatasetWith("dst", T_NATIVE_INT32, dspace, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	for i := 0; i < n; i++ {
+		info, err := src.ChunkInfo(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Offset[0]%2 != 0 || info.Offset[1]%3 != 0 || info.Addr == 0 || info.Size == 0 {
+			t.Errorf("unexpected chunk info: %+v", info)
+		}
+		raw, mask, err := src.ReadChunk(info.Offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uint(len(raw)) != info.Size || mask != info.FilterMask {
+			t.Errorf("chunk %v: got %d bytes with mask %#x, want %d bytes with mask %#x", info.Offset, len(raw), mask, info.Size, info.FilterMask)
+		}
+
+		// The raw chunk is the deflated data of the chunk.
+		zr, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row, col := int(info.Offset[0]), int(info.Offset[1])
+		for j := 0; j < 2*3; j++ {
+			want := data[(row+j/3)*6+col+j%3]
+			if got := int32(binary.NativeEndian.Uint32(plain[4*j:])); got != want {
+				t.Errorf("chunk %v: wrong value at %d: got %d, want %d", info.Offset, j, got, want)
+			}
+		}
+
+		if err := dst.WriteChunk(info.Offset, mask, raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := make([]int32, len(data))
+	if err = dst.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("wrong data after copying chunks: got %v, want %v", got, data)
+	}
+}
+
+func TestMemTypeConversion(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	// Big-endian single precision floats in the file.
+	depth, err := f.CreateDataset("depth", T_IEEE_F32BE, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer depth.Close()
+	values := []float64{0.5, 1.5, -2.25, 3, 4.75, -5}
+	if err := depth.Write(&values); err != nil {
+		t.Fatal(err)
+	}
+	got64 := make([]float64, 6)
+	if err := depth.Read(&got64); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got64, values) {
+		t.Errorf("wrong float64 data: got %v, want %v", got64, values)
+	}
+	var got32 [2][3]float32
+	if err := depth.Read(&got32); err != nil {
+		t.Fatal(err)
+	}
+	if want := [2][3]float32{{0.5, 1.5, -2.25}, {3, 4.75, -5}}; got32 != want {
+		t.Errorf("wrong float32 data: got %v, want %v", got32, want)
+	}
+	var wrong []struct{ A, B float64 }
+	if err := depth.Read(&wrong); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType reading floats into structs, got %v", err)
+	}
+
+	// Big-endian 16-bit codes, written and read with explicit memory types.
+	codes, err := f.CreateDataset("codes", T_STD_I16BE, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer codes.Close()
+	in := []int64{-300, -1, 0, 1, 2, 300}
+	if err := codes.WriteAs(&in, T_NATIVE_INT64); err != nil {
+		t.Fatal(err)
+	}
+	out := make([]int32, 6)
+	if err := codes.ReadAs(&out, T_NATIVE_INT32); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{-300, -1, 0, 1, 2, 300}; !reflect.DeepEqual(out, want) {
+		t.Errorf("wrong int32 data: got %v, want %v", out, want)
+	}
+	ints := make([]int, 6)
+	if err := codes.Read(&ints); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{-300, -1, 0, 1, 2, 300}; !reflect.DeepEqual(ints, want) {
+		t.Errorf("wrong int data: got %v, want %v", ints, want)
+	}
+}
+
+func TestMemTypeFallback(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	// Opaque data is read and written as raw bytes.
+	otype, err := CreateDatatype(T_OPAQUE, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer otype.Close()
+	blob, err := f.CreateDataset("blob", otype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blob.Close()
+	raw := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if err := blob.Write(&raw); err != nil {
+		t.Fatal(err)
+	}
+	gotRaw := make([]byte, len(raw))
+	if err := blob.Read(&gotRaw); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotRaw, raw) {
+		t.Errorf("wrong opaque data: got %v, want %v", gotRaw, raw)
+	}
+
+	// Compounds with a fixed-length string member, as written by C tools,
+	// are copied with the layout of the file.
+	type record struct {
+		Name  [8]byte
+		Value int32
+	}
+	name := FixedGoStringDatatype(8)
+	defer name.Close()
+	ctype, err := NewCompoundType(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctype.Close()
+	if err := ctype.Insert("Name", 0, name); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctype.Insert("Value", 8, T_NATIVE_INT32); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := f.CreateDataset("records", &ctype.Datatype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recs.Close()
+	want := []record{{Value: 1}, {Value: -2}, {Value: 3}}
+	for i, n := range []string{"inflow", "outflow", "stage"} {
+		copy(want[i].Name[:], n)
+	}
+	if err := recs.Write(&want); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]record, len(want))
+	if err := recs.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong records: got %v, want %v", got, want)
+	}
+
+	// Members that do not match the fields by name are not converted.
+	renamed := make([]struct {
+		Label [8]byte
+		Count int32
+	}, len(want))
+	if err := recs.Read(&renamed); !errors.Is(err, ErrBadType) {
+		t.Errorf("reading records into renamed fields: got %v, want ErrBadType", err)
+	}
+
+	// Elements of another size than those of the dataset are rejected.
+	words := make([]uint16, 6)
+	if err := blob.Read(&words); !errors.Is(err, ErrBadType) {
+		t.Errorf("reading opaque data into uint16: got %v, want ErrBadType", err)
+	}
+	if err := blob.ReadAs(&words, T_NATIVE_UINT16); !errors.Is(err, ErrBadType) {
+		t.Errorf("reading opaque data as uint16: got %v, want ErrBadType", err)
+	}
+
+	// ReadAs checks the length of the buffer and reads as Read without a
+	// memory datatype.
+	short := make([]byte, 8)
+	if err := blob.ReadAs(&short, otype); err == nil {
+		t.Error("expected an error reading into a short buffer")
+	}
+	gotRaw = make([]byte, len(raw))
+	if err := blob.ReadAs(&gotRaw, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotRaw, raw) {
+		t.Errorf("wrong opaque data read with a nil memory datatype: got %v, want %v", gotRaw, raw)
+	}
+}
+
+func TestStagedReadWrite(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	counts := []int16{7, 8, 9}
+	records := []stagedRecord{
+		{Flag: 1, Name: "one", Values: []int32{1, 2, 3}, Tags: [2]string{"a", "b"}, Weight: 0.5, count: &counts[0]},
+		{Flag: 2, Name: "two", Values: []int32{}, Tags: [2]string{"c", ""}, count: &counts[1]},
+		{Flag: 3, Values: []int32{-4}, Weight: 3.25, count: &counts[2]},
+	}
+	dtype, err := NewDatatypeFromValue(records[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	dset, err := f.CreateDataset("records", dtype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	if err := dset.Write(&records); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]stagedRecord, 3)
+	if err := dset.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("wrong records:\ngot= %+v\nwant=%+v", got, records)
+	}
+	if err := dset.Read(make([]stagedRecord, 2)); err == nil {
+		t.Error("expected an error reading into a short buffer")
+	}
+
+	names, err := f.CreateDataset("names", T_GO_STRING, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer names.Close()
+	if err := names.Write(&[3]string{"zero", "", "two"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the last name into the middle of a buffer, leaving the
+	// other elements untouched.
+	filespace := names.Space()
+	defer filespace.Close()
+	if err := filespace.SelectElements([][]uint{{2}}); err != nil {
+		t.Fatal(err)
+	}
+	memspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memspace.Close()
+	if err := memspace.SelectElements([][]uint{{1}}); err != nil {
+		t.Fatal(err)
+	}
+	buf := []string{"a", "b", "c"}
+	if err := names.ReadSubset(&buf, memspace, filespace); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "two", "c"}; !reflect.DeepEqual(buf, want) {
+		t.Errorf("wrong names: got %q, want %q", buf, want)
+	}
+}
+
+func TestStringDatasets(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{2, 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	want := []string{"a", "bb", "", "seven.."}
+	fixed8 := FixedGoStringDatatype(8)
+	defer fixed8.Close()
+	for _, dtype := range []*Datatype{T_GO_STRING, fixed8} {
+		name := "vlen"
+		if isFixedString(dtype) {
+			name = "fixed"
+		}
+		dset, err := f.CreateDataset(name, dtype, space)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer dset.Close()
+		if err := dset.Write(&want); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got := make([]string, 4)
+		if err := dset.Read(&got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: wrong strings: got %q, want %q", name, got, want)
+		}
+		var arr [2][2]string
+		if err := dset.Read(&arr); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if arr != [2][2]string{{"a", "bb"}, {"", "seven.."}} {
+			t.Errorf("%s: wrong strings: got %q, want %q", name, arr, want)
+		}
+	}
+
+	fixed, err := f.OpenDataset("fixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fixed.Close()
+	long := []string{"a", "bb", "", "eight..."}
+	if err := fixed.Write(&long); err == nil {
+		t.Error("expected an error writing a string longer than the fixed length")
+	}
+	fixed.SetStringOptions(StringOptions{Truncate: true})
+	if err := fixed.Write(&long); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the last string into the first element of a buffer.
+	filespace := fixed.Space()
+	defer filespace.Close()
+	if err := filespace.SelectElements([][]uint{{1, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	memspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memspace.Close()
+	if err := memspace.SelectElements([][]uint{{0}}); err != nil {
+		t.Fatal(err)
+	}
+	buf := []string{"x", "y"}
+	if err := fixed.ReadSubset(&buf, memspace, filespace); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"eight..", "y"}; !reflect.DeepEqual(buf, want) {
+		t.Errorf("wrong truncated string: got %q, want %q", buf, want)
+	}
+
+	spaceType, err := T_C_S1.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spaceType.Close()
+	if err := spaceType.SetSize(6); err != nil {
+		t.Fatal(err)
+	}
+	if err := spaceType.SetStrPad(T_STR_SPACEPAD); err != nil {
+		t.Fatal(err)
+	}
+	padded, err := f.CreateDataset("padded", spaceType, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer padded.Close()
+	if err := padded.Write(&[4]string{" a", "b ", "", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		opts StringOptions
+		want []string
+	}{
+		{want: []string{" a", "b", "", "c"}},
+		{opts: StringOptions{TrimSpace: true}, want: []string{"a", "b", "", "c"}},
+		{opts: StringOptions{KeepPadding: true}, want: []string{" a    ", "b     ", "      ", "c     "}},
+	} {
+		padded.SetStringOptions(test.opts)
+		got := make([]string, 4)
+		if err := padded.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("wrong strings with %+v: got %q, want %q", test.opts, got, test.want)
+		}
+	}
+
+	// Strings are staged with explicit memory datatypes too.
+	vlen, err := f.OpenDataset("vlen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vlen.Close()
+	if err := vlen.WriteAs(&[]string{"w", "x", "y", "z"}, T_GO_STRING); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 4)
+	if err := vlen.ReadAs(&got, T_GO_STRING); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"w", "x", "y", "z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong strings with an explicit memory datatype: got %q, want %q", got, want)
+	}
+	if err := vlen.ReadAs(&got, T_NATIVE_INT32); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType reading strings as 32-bit integers, got %v", err)
+	}
+	if err := fixed.ReadAs(&got, fixed8); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "bb", "", "eight.."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong fixed-length strings with an explicit memory datatype: got %q, want %q", got, want)
+	}
+}
+
+func TestVarLenDatasets(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	// Sequences of single precision floats in the file.
+	vlf, err := NewVarLenType(T_NATIVE_FLOAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vlf.Close()
+	floats, err := f.CreateDataset("floats", &vlf.Datatype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer floats.Close()
+	want := [][]float64{{1, 2.5}, nil, {-3, 4, 5.25}}
+	if err := floats.Write(&want); err != nil {
+		t.Fatal(err)
+	}
+	got := make([][]float64, 3)
+	if err := floats.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{1, 2.5}, {}, {-3, 4, 5.25}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong sequences: got %v, want %v", got, want)
+	}
+	var got32 [3][]int32
+	if err := floats.Read(&got32); err != nil {
+		t.Fatal(err)
+	}
+	if want := [3][]int32{{1, 2}, {}, {-3, 4, 5}}; !reflect.DeepEqual(got32, want) {
+		t.Errorf("wrong converted sequences: got %v, want %v", got32, want)
+	}
+	if err := floats.Read(&[][]string{nil, nil, nil}); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType reading float sequences into strings, got %v", err)
+	}
+
+	// Read the last sequence into the first element of a buffer.
+	filespace := floats.Space()
+	defer filespace.Close()
+	if err := filespace.SelectHyperslab([]uint{2}, nil, []uint{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	memspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memspace.Close()
+	if err := memspace.SelectHyperslab([]uint{0}, nil, []uint{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := [][]float64{{0}, {9}}
+	if err := floats.ReadSubset(&buf, memspace, filespace); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{-3, 4, 5.25}, {9}}; !reflect.DeepEqual(buf, want) {
+		t.Errorf("wrong sequences: got %v, want %v", buf, want)
+	}
+
+	// Face-point lists per cell.
+	type cell struct {
+		ID         int32
+		FacePoints []int32
+		Area       float64
+	}
+	cells := []cell{
+		{ID: 0, FacePoints: []int32{0, 1, 2, 3}, Area: 1.5},
+		{ID: 1, FacePoints: []int32{3, 2, 4}, Area: 0.75},
+		{ID: 2, FacePoints: []int32{}, Area: 0},
+	}
+	dtype, err := NewDatatypeFromValue(cell{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	mesh, err := f.CreateDataset("cells", dtype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mesh.Close()
+	if err := mesh.Write(&cells); err != nil {
+		t.Fatal(err)
+	}
+	gotCells := make([]cell, 3)
+	if err := mesh.Read(&gotCells); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotCells, cells) {
+		t.Errorf("wrong cells:\ngot= %+v\nwant=%+v", gotCells, cells)
+	}
+
+	// Members are converted by name.
+	var points [3]struct {
+		FacePoints []int64
+	}
+	if err := mesh.Read(&points); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{3, 2, 4}; !reflect.DeepEqual(points[1].FacePoints, want) {
+		t.Errorf("wrong face points: got %v, want %v", points[1].FacePoints, want)
+	}
+}
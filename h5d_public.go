@@ -7,6 +7,8 @@ package hdf5
 // #include "hdf5.h"
 import "C"
 
+import "fmt"
+
 // Used to unset chunk cache configuration parameter.
 // https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetChunkCache
 const (
@@ -14,3 +16,125 @@ const (
 	D_CHUNK_CACHE_NBYTES_DEFAULT int     = -1 // The total size of the raw data chunk cache for this dataset
 	D_CHUNK_CACHE_W0_DEFAULT     float64 = -1 // The chunk preemption policy for this dataset
 )
+
+// Layout is the storage layout of the raw data of a dataset.
+type Layout C.H5D_layout_t
+
+const (
+	D_LAYOUT_ERROR Layout = C.H5D_LAYOUT_ERROR // error
+	D_COMPACT      Layout = C.H5D_COMPACT      // raw data stored in the object header, for small datasets
+	D_CONTIGUOUS   Layout = C.H5D_CONTIGUOUS   // raw data stored in one block of the file
+	D_CHUNKED      Layout = C.H5D_CHUNKED      // raw data stored in chunks, see PropList.SetChunk
+	D_VIRTUAL      Layout = C.H5D_VIRTUAL      // raw data mapped from other datasets
+)
+
+func (l Layout) String() string {
+	switch l {
+	case D_COMPACT:
+		return "compact"
+	case D_CONTIGUOUS:
+		return "contiguous"
+	case D_CHUNKED:
+		return "chunked"
+	case D_VIRTUAL:
+		return "virtual"
+	default:
+		return fmt.Sprintf("Layout(%d)", int(l))
+	}
+}
+
+// AllocTime is the time at which the storage space of a dataset is allocated.
+type AllocTime C.H5D_alloc_time_t
+
+const (
+	D_ALLOC_TIME_ERROR   AllocTime = C.H5D_ALLOC_TIME_ERROR   // error
+	D_ALLOC_TIME_DEFAULT AllocTime = C.H5D_ALLOC_TIME_DEFAULT // default time of the layout
+	D_ALLOC_TIME_EARLY   AllocTime = C.H5D_ALLOC_TIME_EARLY   // when the dataset is created
+	D_ALLOC_TIME_LATE    AllocTime = C.H5D_ALLOC_TIME_LATE    // when the dataset is first written
+	D_ALLOC_TIME_INCR    AllocTime = C.H5D_ALLOC_TIME_INCR    // chunk by chunk, as they are written
+)
+
+func (t AllocTime) String() string {
+	switch t {
+	case D_ALLOC_TIME_DEFAULT:
+		return "default"
+	case D_ALLOC_TIME_EARLY:
+		return "early"
+	case D_ALLOC_TIME_LATE:
+		return "late"
+	case D_ALLOC_TIME_INCR:
+		return "incremental"
+	default:
+		return fmt.Sprintf("AllocTime(%d)", int(t))
+	}
+}
+
+// FillTime is the time at which the fill value is written to the storage
+// space of a dataset.
+type FillTime C.H5D_fill_time_t
+
+const (
+	D_FILL_TIME_ERROR FillTime = C.H5D_FILL_TIME_ERROR // error
+	D_FILL_TIME_ALLOC FillTime = C.H5D_FILL_TIME_ALLOC // when the space is allocated
+	D_FILL_TIME_NEVER FillTime = C.H5D_FILL_TIME_NEVER // never
+	D_FILL_TIME_IFSET FillTime = C.H5D_FILL_TIME_IFSET // when the space is allocated, if a fill value was set
+)
+
+func (t FillTime) String() string {
+	switch t {
+	case D_FILL_TIME_ALLOC:
+		return "alloc"
+	case D_FILL_TIME_NEVER:
+		return "never"
+	case D_FILL_TIME_IFSET:
+		return "ifset"
+	default:
+		return fmt.Sprintf("FillTime(%d)", int(t))
+	}
+}
+
+// FillValueStatus tells whether the fill value of a property list is set.
+type FillValueStatus C.H5D_fill_value_t
+
+const (
+	D_FILL_VALUE_ERROR        FillValueStatus = C.H5D_FILL_VALUE_ERROR        // error
+	D_FILL_VALUE_UNDEFINED    FillValueStatus = C.H5D_FILL_VALUE_UNDEFINED    // no fill value
+	D_FILL_VALUE_DEFAULT      FillValueStatus = C.H5D_FILL_VALUE_DEFAULT      // fill value of the library, zero
+	D_FILL_VALUE_USER_DEFINED FillValueStatus = C.H5D_FILL_VALUE_USER_DEFINED // fill value set with PropList.SetFillValue
+)
+
+func (s FillValueStatus) String() string {
+	switch s {
+	case D_FILL_VALUE_UNDEFINED:
+		return "undefined"
+	case D_FILL_VALUE_DEFAULT:
+		return "default"
+	case D_FILL_VALUE_USER_DEFINED:
+		return "user-defined"
+	default:
+		return fmt.Sprintf("FillValueStatus(%d)", int(s))
+	}
+}
+
+// SpaceStatus tells whether the storage space of a dataset is allocated.
+type SpaceStatus C.H5D_space_status_t
+
+const (
+	D_SPACE_STATUS_ERROR          SpaceStatus = C.H5D_SPACE_STATUS_ERROR          // error
+	D_SPACE_STATUS_NOT_ALLOCATED  SpaceStatus = C.H5D_SPACE_STATUS_NOT_ALLOCATED  // no space allocated
+	D_SPACE_STATUS_PART_ALLOCATED SpaceStatus = C.H5D_SPACE_STATUS_PART_ALLOCATED // some chunks allocated
+	D_SPACE_STATUS_ALLOCATED      SpaceStatus = C.H5D_SPACE_STATUS_ALLOCATED      // all the space allocated
+)
+
+func (s SpaceStatus) String() string {
+	switch s {
+	case D_SPACE_STATUS_NOT_ALLOCATED:
+		return "not allocated"
+	case D_SPACE_STATUS_PART_ALLOCATED:
+		return "partly allocated"
+	case D_SPACE_STATUS_ALLOCATED:
+		return "allocated"
+	default:
+		return fmt.Sprintf("SpaceStatus(%d)", int(s))
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// datasetLocation is a location holding datasets, a *File or a *Group.
+type datasetLocation interface {
+	CreateDatasetWith(name string, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error)
+	OpenDataset(name string) (*Dataset, error)
+}
+
+// TypedDataset is a Dataset whose elements are read and written as Go
+// values of type T.
+type TypedDataset[T any] struct {
+	*Dataset
+}
+
+// CreateTypedDataset creates the dataset name in loc, a *File or a *Group,
+// with dimensions dims and the datatype of T. The dataset creation property
+// list dcpl may be nil. The returned dataset must be closed by the user when
+// it is no longer needed.
+func CreateTypedDataset[T any](loc datasetLocation, name string, dims []uint, dcpl *PropList) (*TypedDataset[T], error) {
+	t, err := typedElem[T]()
+	if err != nil {
+		return nil, err
+	}
+	dtype, err := NewDataTypeFromType(t)
+	if err != nil {
+		return nil, err
+	}
+	defer dtype.Close()
+	space, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer space.Close()
+	if dcpl == nil {
+		dcpl = P_DEFAULT
+	}
+	ds, err := loc.CreateDatasetWith(name, dtype, space, dcpl)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDataset[T]{ds}, nil
+}
+
+// OpenTypedDataset opens the dataset name in loc, a *File or a *Group, and
+// checks that its elements may be converted to and from T. The returned
+// dataset must be closed by the user when it is no longer needed.
+func OpenTypedDataset[T any](loc datasetLocation, name string) (*TypedDataset[T], error) {
+	ds, err := loc.OpenDataset(name)
+	if err != nil {
+		return nil, err
+	}
+	mtype, err := typedMemType[T](ds)
+	if err != nil {
+		ds.Close()
+		return nil, err
+	}
+	mtype.Close()
+	return &TypedDataset[T]{ds}, nil
+}
+
+// ReadAll reads all the elements of the dataset.
+func (ds *TypedDataset[T]) ReadAll() ([]T, error) {
+	return ReadAll[T](ds.Dataset)
+}
+
+// ReadSelection reads the elements selected in sel, see ReadSelection.
+func (ds *TypedDataset[T]) ReadSelection(sel *Dataspace) ([]T, error) {
+	return ReadSelection[T](ds.Dataset, sel)
+}
+
+// Write writes all the elements of the dataset.
+func (ds *TypedDataset[T]) Write(data []T) error {
+	return Write(ds.Dataset, data)
+}
+
+// ReadAll reads all the elements of ds as values of type T, in row-major
+// order. It returns an error if the elements of ds may not be converted
+// to T.
+func ReadAll[T any](ds *Dataset) ([]T, error) {
+	space := ds.Space()
+	if space == nil {
+		return nil, fmt.Errorf("couldn't open Dataspace from Dataset %q", ds.Name())
+	}
+	defer space.Close()
+	return readTyped[T](ds, space.SimpleExtentNPoints(), nil, nil)
+}
+
+// ReadSelection reads the elements of ds selected in sel, a copy of the
+// dataspace of ds with a selection such as a hyperslab or points. The
+// elements are returned in the order of the selection. A nil sel selects all
+// the elements of ds, as in ReadAll.
+func ReadSelection[T any](ds *Dataset, sel *Dataspace) ([]T, error) {
+	if sel == nil {
+		return ReadAll[T](ds)
+	}
+	n, err := sel.SelectionNPoints()
+	if err != nil {
+		return nil, err
+	}
+	memspace, err := CreateSimpleDataspace([]uint{uint(n)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer memspace.Close()
+	return readTyped[T](ds, n, memspace, sel)
+}
+
+// Write writes data as all the elements of ds, in row-major order. The
+// length of data must be the number of elements of ds.
+func Write[T any](ds *Dataset, data []T) error {
+	defer lockThread()()
+	mtype, err := typedMemType[T](ds)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+
+	space := ds.Space()
+	if space == nil {
+		return fmt.Errorf("couldn't open Dataspace from Dataset %q", ds.Name())
+	}
+	n := space.SimpleExtentNPoints()
+	space.Close()
+	if len(data) != n {
+		return fmt.Errorf("hdf5: wrong number of elements for dataset %q: got %d, want %d", ds.Name(), len(data), n)
+	}
+	if n == 0 {
+		return nil
+	}
+	return h5err(C.H5Dwrite(ds.id, mtype.id, 0, 0, 0, unsafe.Pointer(&data[0])))
+}
+
+// readTyped reads the n elements of ds selected in filespace into memspace.
+func readTyped[T any](ds *Dataset, n int, memspace, filespace *Dataspace) ([]T, error) {
+	defer lockThread()()
+	mtype, err := typedMemType[T](ds)
+	if err != nil {
+		return nil, err
+	}
+	defer mtype.Close()
+
+	data := make([]T, n)
+	if n == 0 {
+		return data, nil
+	}
+	var memspace_id, filespace_id C.hid_t
+	if memspace != nil {
+		memspace_id = memspace.id
+	}
+	if filespace != nil {
+		filespace_id = filespace.id
+	}
+	err = h5err(C.H5Dread(ds.id, mtype.id, memspace_id, filespace_id, 0, unsafe.Pointer(&data[0])))
+	if err != nil {
+		return nil, ds.filterError(err)
+	}
+	return data, nil
+}
+
+// typedMemType returns the memory datatype of T after checking that the
+// elements of ds may be converted to and from T.
+func typedMemType[T any](ds *Dataset) (*Datatype, error) {
+	t, err := typedElem[T]()
+	if err != nil {
+		return nil, err
+	}
+	return ds.memTypeOf(t)
+}
+
+// typedElem returns the reflect.Type of T after checking that its values
+// may be passed to the library as they are stored in memory.
+func typedElem[T any]() (reflect.Type, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if !isFixedSize(t) {
+		return nil, fmt.Errorf("hdf5: unsupported element type %v: %w", t, ErrBadType)
+	}
+	return t, nil
+}
+
+// isFixedSize returns whether t is made of numbers and booleans only, so
+// that its memory holds no Go pointers.
+func isFixedSize(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Array:
+		return isFixedSize(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isFixedSize(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// convertibleClasses returns whether the library converts between elements
+// of the classes file and mem.
+func convertibleClasses(file, mem TypeClass) bool {
+	if file == mem {
+		return true
+	}
+	numeric := func(c TypeClass) bool { return c == T_INTEGER || c == T_FLOAT }
+	return numeric(file) && numeric(mem)
+}
+
+// convertibleTypes returns whether the library converts between elements of
+// the datatypes file and mem, comparing the base types of variable-length
+// sequences and arrays, and the members of compounds.
+func convertibleTypes(file, mem *Datatype) bool {
+	class := file.Class()
+	if !convertibleClasses(class, mem.Class()) {
+		return false
+	}
+	switch class {
+	case T_COMPOUND:
+		return convertibleMembers(&CompoundType{*file}, &CompoundType{*mem})
+	case T_VLEN, T_ARRAY:
+	default:
+		return true
+	}
+	fsuper, err := file.SuperType()
+	if err != nil {
+		return false
+	}
+	defer fsuper.Close()
+	msuper, err := mem.SuperType()
+	if err != nil {
+		return false
+	}
+	defer msuper.Close()
+	return convertibleTypes(fsuper, msuper)
+}
+
+// convertibleMembers returns whether each member of the compound mem has a
+// member of the same name in file that the library converts to it. Members
+// are converted by name, so the others would be left unset.
+func convertibleMembers(file, mem *CompoundType) bool {
+	for i := 0; i < mem.NMembers(); i++ {
+		j := file.MemberIndex(mem.MemberName(i))
+		if j < 0 {
+			return false
+		}
+		ftype, err := file.MemberType(j)
+		if err != nil {
+			return false
+		}
+		mtype, err := mem.MemberType(i)
+		if err != nil {
+			ftype.Close()
+			return false
+		}
+		ok := convertibleTypes(ftype, mtype)
+		ftype.Close()
+		mtype.Close()
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
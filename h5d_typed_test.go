@@ -0,0 +1,130 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type typedPoint struct {
+	X, Y  float64
+	Index int32
+	Flags [2]uint8
+}
+
+func TestTypedDataset(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ds, err := CreateTypedDataset[typedPoint](f, "points", []uint{2, 3}, nil)
+	if err != nil {
+		t.Fatalf("CreateTypedDataset failed: %s", err)
+	}
+	defer ds.Close()
+	want := make([]typedPoint, 6)
+	for i := range want {
+		want[i] = typedPoint{X: float64(i), Y: -float64(i), Index: int32(i), Flags: [2]uint8{uint8(i), 1}}
+	}
+	if err := ds.Write(want[:5]); err == nil {
+		t.Error("expected an error writing too few elements")
+	}
+	if err := ds.Write(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ds.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong data: got %v, want %v", got, want)
+	}
+
+	sel := ds.Space()
+	defer sel.Close()
+	if err := sel.SelectElements([][]uint{{1, 2}, {0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	some, err := ds.ReadSelection(sel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(some) != 2 || some[0] != want[5] || some[1] != want[1] {
+		t.Errorf("wrong selection: got %v, want %v", some, []typedPoint{want[5], want[1]})
+	}
+	all, err := ds.ReadSelection(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("wrong data for a nil selection: got %v, want %v", all, want)
+	}
+
+	if _, err := OpenTypedDataset[int32](f, "points"); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType opening compound dataset as int32, got %v", err)
+	}
+	if _, err := CreateTypedDataset[string](f, "names", []uint{2}, nil); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType creating a dataset of strings, got %v", err)
+	}
+}
+
+func TestTypedConversion(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{4}); err != nil {
+		t.Fatal(err)
+	}
+	ds, err := CreateTypedDataset[int16](f, "values", []uint{4}, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(ds.Dataset, []int16{-2, -1, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	ds.Close()
+
+	// The elements are converted to the Go type.
+	f64, err := OpenTypedDataset[float64](f, "values")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f64.Close()
+	got, err := f64.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{-2, -1, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong converted data: got %v, want %v", got, want)
+	}
+	i64, err := ReadAll[int64](f64.Dataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{-2, -1, 1, 2}; !reflect.DeepEqual(i64, want) {
+		t.Errorf("wrong converted data: got %v, want %v", i64, want)
+	}
+	if _, err := ReadAll[typedPoint](f64.Dataset); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType reading integers as compound, got %v", err)
+	}
+	if _, err := ReadAll[map[string]int](f64.Dataset); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType reading into maps, got %v", err)
+	}
+}
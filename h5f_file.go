@@ -17,13 +17,15 @@ import (
 
 // File constants
 const (
-	F_ACC_RDONLY  int = 0x0000 // absence of rdwr => rd-only
-	F_ACC_RDWR    int = 0x0001 // open for read and write
-	F_ACC_TRUNC   int = 0x0002 // Truncate file, if it already exists, erasing all data previously stored in the file.
-	F_ACC_EXCL    int = 0x0004 // Fail if file already exists.
-	F_ACC_DEBUG   int = 0x0008 // print debug info
-	F_ACC_CREAT   int = 0x0010 // create non-existing files
-	F_ACC_DEFAULT int = 0xffff // value passed to set_elink_acc_flags to cause flags to be taken from the parent file
+	F_ACC_RDONLY     int = 0x0000 // absence of rdwr => rd-only
+	F_ACC_RDWR       int = 0x0001 // open for read and write
+	F_ACC_TRUNC      int = 0x0002 // Truncate file, if it already exists, erasing all data previously stored in the file.
+	F_ACC_EXCL       int = 0x0004 // Fail if file already exists.
+	F_ACC_DEBUG      int = 0x0008 // print debug info
+	F_ACC_CREAT      int = 0x0010 // create non-existing files
+	F_ACC_SWMR_WRITE int = 0x0020 // open for single-writer/multiple-reader writing
+	F_ACC_SWMR_READ  int = 0x0040 // open for single-writer/multiple-reader reading
+	F_ACC_DEFAULT    int = 0xffff // value passed to set_elink_acc_flags to cause flags to be taken from the parent file
 )
 
 // The difference between a single file and a set of mounted files.
@@ -34,51 +36,132 @@ const (
 	F_SCOPE_GLOBAL Scope = 1 // entire virtual file.
 )
 
+// LibverBound is a version of the HDF5 library used as lower or upper bound
+// of the versions of the objects created in a file.
+type LibverBound C.H5F_libver_t
+
+const (
+	F_LIBVER_EARLIEST LibverBound = C.H5F_LIBVER_EARLIEST // Earliest possible format version
+	F_LIBVER_V18      LibverBound = C.H5F_LIBVER_V18      // Format version of HDF5 1.8
+	F_LIBVER_V110     LibverBound = C.H5F_LIBVER_V110     // Format version of HDF5 1.10
+	F_LIBVER_LATEST   LibverBound = C.H5F_LIBVER_LATEST   // Latest format version of the library
+)
+
+// FileSpaceStrategy is the strategy used to manage the free space of a file.
+type FileSpaceStrategy C.H5F_fspace_strategy_t
+
+const (
+	F_FSPACE_STRATEGY_FSM_AGGR FileSpaceStrategy = C.H5F_FSPACE_STRATEGY_FSM_AGGR // Free-space managers, aggregators and virtual file driver (default)
+	F_FSPACE_STRATEGY_PAGE     FileSpaceStrategy = C.H5F_FSPACE_STRATEGY_PAGE     // Free-space managers with paged aggregation
+	F_FSPACE_STRATEGY_AGGR     FileSpaceStrategy = C.H5F_FSPACE_STRATEGY_AGGR     // Aggregators and virtual file driver
+	F_FSPACE_STRATEGY_NONE     FileSpaceStrategy = C.H5F_FSPACE_STRATEGY_NONE     // Virtual file driver only
+)
+
 // a HDF5 file
 type File struct {
 	CommonFG
 }
 
 func newFile(id C.hid_t) *File {
-	return &File{CommonFG{Identifier{id}}}
+	return &File{CommonFG{Location{Identifier{id}}}}
 }
 
-// Creates an HDF5 file.
+// Creates an HDF5 file. If flags has F_ACC_SWMR_WRITE set, the file is
+// created with the latest format, which SWMR access requires.
 func CreateFile(name string, flags int) (*File, error) {
+	fapl, err := swmrAccessPropList(flags)
+	if err != nil {
+		return nil, err
+	}
+	if fapl != P_DEFAULT {
+		defer fapl.Close()
+	}
+	return CreateFileWith(name, flags, P_DEFAULT, fapl)
+}
+
+// CreateFileWith creates an HDF5 file with the file creation property list
+// fcpl and the file access property list fapl. The returned file must be
+// closed by the user when it is no longer needed.
+func CreateFileWith(name string, flags int, fcpl, fapl *PropList) (*File, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
-	// FIXME: file props
-	hid := C.H5Fcreate(c_name, C.uint(flags), P_DEFAULT.id, P_DEFAULT.id)
+	hid := C.H5Fcreate(c_name, C.uint(flags), fcpl.id, fapl.id)
 	if err := checkID(hid); err != nil {
-		return nil, fmt.Errorf("error creating hdf5 file: %s", err)
+		return nil, fmt.Errorf("error creating hdf5 file: %w", err)
 	}
 	return newFile(hid), nil
 }
 
 // Open opens and returns an an existing HDF5 file. The returned
 // file must be closed by the user when it is no longer needed.
+//
+// A file written by a single process may be read concurrently by others
+// (SWMR) when it is opened with F_ACC_RDWR|F_ACC_SWMR_WRITE by the writer
+// and with F_ACC_RDONLY|F_ACC_SWMR_READ by the readers. Readers see new data
+// after calling Dataset.Refresh.
 func OpenFile(name string, flags int) (*File, error) {
-	c_name := C.CString(name)
-	defer C.free(unsafe.Pointer(c_name))
+	fapl, err := swmrAccessPropList(flags)
+	if err != nil {
+		return nil, err
+	}
+	if fapl != P_DEFAULT {
+		defer fapl.Close()
+	}
+	return OpenFileWithProp(name, flags, fapl)
+}
 
-	// FIXME: file props
-	hid := C.H5Fopen(c_name, C.uint(flags), P_DEFAULT.id)
-	if err := checkID(hid); err != nil {
-		return nil, fmt.Errorf("error opening hdf5 file: %s", err)
+// swmrAccessPropList returns the file access property list used by CreateFile
+// and OpenFile. Writing in SWMR mode needs the latest file format, so the
+// library version bounds are set when flags has F_ACC_SWMR_WRITE.
+func swmrAccessPropList(flags int) (*PropList, error) {
+	if flags&F_ACC_SWMR_WRITE == 0 {
+		return P_DEFAULT, nil
 	}
-	return newFile(hid), nil
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		return nil, err
+	}
+	if err := fapl.SetLibverBounds(F_LIBVER_LATEST, F_LIBVER_LATEST); err != nil {
+		fapl.Close()
+		return nil, err
+	}
+	return fapl, nil
 }
 
 // Open opens using a proplist and returns an an existing HDF5 file. The returned
 // file must be closed by the user when it is no longer needed.
 func OpenFileWithProp(name string, flags int, pl *PropList) (*File, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
 	hid := C.H5Fopen(c_name, C.uint(flags), pl.id)
 	if err := checkID(hid); err != nil {
-		return nil, fmt.Errorf("error opening hdf5 file: %s", err)
+		return nil, fmt.Errorf("error opening hdf5 file: %w", err)
+	}
+	return newFile(hid), nil
+}
+
+// OpenFileImage opens an HDF5 file from its image in memory, such as the
+// content of an HDF5 file read from a network connection. The file is opened
+// read-only unless flags has F_ACC_RDWR set, in which case changes are made
+// to a copy of the image that is retrieved with File.Image. The returned
+// file must be closed by the user when it is no longer needed.
+func OpenFileImage(image []byte, flags int) (*File, error) {
+	defer lockThread()()
+	if len(image) == 0 {
+		return nil, fmt.Errorf("hdf5: empty file image")
+	}
+	var c_flags C.uint
+	if flags&F_ACC_RDWR != 0 {
+		c_flags |= C.H5LT_FILE_IMAGE_OPEN_RW
+	}
+	// The image is copied by the library, so it may be reused by the caller.
+	hid := C.H5LTopen_file_image(unsafe.Pointer(&image[0]), C.size_t(len(image)), c_flags)
+	if err := checkID(hid); err != nil {
+		return nil, fmt.Errorf("error opening hdf5 file image: %w", err)
 	}
 	return newFile(hid), nil
 }
@@ -86,6 +169,7 @@ func OpenFileWithProp(name string, flags int, pl *PropList) (*File, error) {
 // ReOpen returns a new identifier for a previously-opened HDF5 file.
 // The returned file must be closed by the user when it is no longer needed.
 func (f *File) ReOpen() (*File, error) {
+	defer lockThread()()
 	hid := C.H5Freopen(f.id)
 	if err := checkID(hid); err != nil {
 		return nil, fmt.Errorf("error reopening hdf5 file: %s", err)
@@ -110,8 +194,63 @@ func h5fclose(id C.hid_t) C.herr_t {
 	return C.H5Fclose(id)
 }
 
+// CreatePropList returns a copy of the file creation property list of the
+// File. The returned property list must be closed by the user when it is no
+// longer needed.
+func (f *File) CreatePropList() (*PropList, error) {
+	defer lockThread()()
+	hid := C.H5Fget_create_plist(f.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	return newPropList(hid), nil
+}
+
+// AccessPropList returns a copy of the file access property list of the
+// File. The returned property list must be closed by the user when it is no
+// longer needed.
+func (f *File) AccessPropList() (*PropList, error) {
+	defer lockThread()()
+	hid := C.H5Fget_access_plist(f.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	return newPropList(hid), nil
+}
+
+// Image returns a copy of the image of the file in memory, as it would be
+// written to disk. It can be used with files opened with the core driver,
+// see PropList.SetFaplCore, to build HDF5 files without touching the disk.
+func (f *File) Image() ([]byte, error) {
+	defer lockThread()()
+	sz := C.H5Fget_file_image(f.id, nil, 0)
+	if sz < 0 {
+		return nil, fmt.Errorf("hdf5: could not get file image size: %w", newErrorStack(int(sz)))
+	}
+	image := make([]byte, int(sz))
+	if sz == 0 {
+		return image, nil
+	}
+	if sz := C.H5Fget_file_image(f.id, unsafe.Pointer(&image[0]), C.size_t(len(image))); sz < 0 {
+		return nil, fmt.Errorf("hdf5: could not get file image: %w", newErrorStack(int(sz)))
+	}
+	return image, nil
+}
+
+// StartSWMRWrite switches a file opened with F_ACC_RDWR to single-writer/
+// multiple-reader mode, after which other processes may open it with
+// F_ACC_SWMR_READ. The file must use the latest format, see
+// PropList.SetLibverBounds. No object may be created in the file after the
+// switch, so all the datasets to write must be created before, with
+// unlimited dimensions to be appended to.
+func (f *File) StartSWMRWrite() error {
+	defer lockThread()()
+	return h5err(C.H5Fstart_swmr_write(f.id))
+}
+
 // Flushes all buffers associated with a file to disk.
 func (f *File) Flush(scope Scope) error {
+	defer lockThread()()
 	// herr_t H5Fflush(hid_t object_id, H5F_scope_t scope )
 	return h5err(C.H5Fflush(f.id, C.H5F_scope_t(scope)))
 }
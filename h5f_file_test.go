@@ -6,6 +6,7 @@ package hdf5
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -141,3 +142,272 @@ func TestClosedFile(t *testing.T) {
 	}
 
 }
+
+func TestCreateFileWith(t *testing.T) {
+	fcpl, err := NewPropList(P_FILE_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fcpl.Close()
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fapl.Close()
+
+	for _, set := range []error{
+		fcpl.SetUserblock(8192),
+		fcpl.SetSizes(8, 8),
+		fcpl.SetSymK(32, 8),
+		fcpl.SetIstoreK(64),
+		fcpl.SetFileSpaceStrategy(F_FSPACE_STRATEGY_PAGE, true, 1),
+		fcpl.SetFileSpacePageSize(8192),
+		fapl.SetLibverBounds(F_LIBVER_V18, F_LIBVER_LATEST),
+	} {
+		if set != nil {
+			t.Fatal(set)
+		}
+	}
+	if err := fcpl.SetUserblock(100); err == nil {
+		t.Error("expected an error for a user block size that is not a power of two")
+	}
+
+	f, err := CreateFileWith(fname, F_ACC_TRUNC, fcpl, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(fname)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = OpenFile(fname, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	plist, err := f.CreatePropList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plist.Close()
+
+	if size, err := plist.Userblock(); err != nil || size != 8192 {
+		t.Errorf("Userblock: got %d, %v, want 8192", size, err)
+	}
+	if addr, size, err := plist.Sizes(); err != nil || addr != 8 || size != 8 {
+		t.Errorf("Sizes: got %d, %d, %v, want 8, 8", addr, size, err)
+	}
+	if ik, lk, err := plist.SymK(); err != nil || ik != 32 || lk != 8 {
+		t.Errorf("SymK: got %d, %d, %v, want 32, 8", ik, lk, err)
+	}
+	if ik, err := plist.IstoreK(); err != nil || ik != 64 {
+		t.Errorf("IstoreK: got %d, %v, want 64", ik, err)
+	}
+	if strategy, persist, threshold, err := plist.FileSpaceStrategy(); err != nil || strategy != F_FSPACE_STRATEGY_PAGE || !persist || threshold != 1 {
+		t.Errorf("FileSpaceStrategy: got %v, %t, %d, %v, want %v, true, 1", strategy, persist, threshold, err, F_FSPACE_STRATEGY_PAGE)
+	}
+	if size, err := plist.FileSpacePageSize(); err != nil || size != 8192 {
+		t.Errorf("FileSpacePageSize: got %d, %v, want 8192", size, err)
+	}
+
+	access, err := f.AccessPropList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer access.Close()
+	if _, high, err := access.LibverBounds(); err != nil || high != F_LIBVER_LATEST {
+		t.Errorf("LibverBounds: got high %v, %v, want %v", high, err, F_LIBVER_LATEST)
+	}
+}
+
+func TestFileImage(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetFaplCore(64<<10, false); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := CreateFileWith("in-memory.h5", F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	space, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dset, err := f.CreateDataset("data", T_NATIVE_INT32, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [4]int32{1, 2, 3, 4}
+	if err := dset.Write(&want); err != nil {
+		t.Fatal(err)
+	}
+	dset.Close()
+	if err := f.Flush(F_SCOPE_GLOBAL); err != nil {
+		t.Fatal(err)
+	}
+	image, err := f.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := os.Stat("in-memory.h5"); !os.IsNotExist(err) {
+		t.Errorf("unexpected file on disk for the core driver without backing store: %v", err)
+	}
+	if len(image) == 0 {
+		t.Fatal("empty file image")
+	}
+
+	f, err = OpenFileImage(image, F_ACC_RDWR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dset, err = f.OpenDataset("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	var got [4]int32
+	if err := dset.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong data from file image: got %v, want %v", got, want)
+	}
+
+	// Changes are made to a copy of the image.
+	added, err := f.CreateGroup("added")
+	if err != nil {
+		t.Fatal(err)
+	}
+	added.Close()
+	if err := f.Flush(F_SCOPE_GLOBAL); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := f.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := OpenFileImage(changed, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	if !g.LinkExists("added") {
+		t.Error(`expected "added" group in the changed image`)
+	}
+	h, err := OpenFileImage(image, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if h.LinkExists("added") {
+		t.Error(`unexpected "added" group in the original image`)
+	}
+
+	if _, err := OpenFileImage([]byte("not an HDF5 file"), F_ACC_RDONLY); err == nil {
+		t.Error("expected an error opening an invalid file image")
+	}
+}
+
+func TestSWMR(t *testing.T) {
+	const dsn = "series"
+	defer os.Remove(fname)
+
+	// Datasets are created before the switch to SWMR mode.
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	space, err := CreateSimpleDataspace([]uint{0}, []uint{S_UNLIMITED})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{16}); err != nil {
+		t.Fatal(err)
+	}
+	dset, err := f.CreateDatasetWith(dsn, T_NATIVE_INT32, space, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dset.Close()
+	if err := f.StartSWMRWrite(); err == nil {
+		t.Error("expected an error starting SWMR mode without the latest file format")
+	}
+	f.Close()
+
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetLibverBounds(F_LIBVER_LATEST, F_LIBVER_LATEST); err != nil {
+		t.Fatal(err)
+	}
+	f, err = CreateFileWith(fname, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dset, err = f.CreateDatasetWith(dsn, T_NATIVE_INT32, space, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.StartSWMRWrite(); err != nil {
+		t.Fatalf("StartSWMRWrite failed: %s", err)
+	}
+	if err := dset.SetExtent([]uint{4}); err != nil {
+		t.Fatal(err)
+	}
+	want := []int32{1, 2, 3, 4}
+	if err := dset.Write(&want); err != nil {
+		t.Fatal(err)
+	}
+	if err := dset.Flush(); err != nil {
+		t.Fatalf("Flush failed: %s", err)
+	}
+	dset.Close()
+	f.Close()
+
+	f, err = OpenFile(fname, F_ACC_RDONLY|F_ACC_SWMR_READ)
+	if err != nil {
+		t.Fatalf("OpenFile with F_ACC_SWMR_READ failed: %s", err)
+	}
+	defer f.Close()
+	dset, err = f.OpenDataset(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	if err := dset.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %s", err)
+	}
+	got := make([]int32, len(want))
+	if err := dset.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong data in SWMR mode: got %v, want %v", got, want)
+	}
+
+	// CreateFile sets up the file format for SWMR writing itself.
+	const swmrName = "test_swmr.h5"
+	defer os.Remove(swmrName)
+	g, err := CreateFile(swmrName, F_ACC_TRUNC|F_ACC_SWMR_WRITE)
+	if err != nil {
+		t.Fatalf("CreateFile with F_ACC_SWMR_WRITE failed: %s", err)
+	}
+	g.Close()
+}
@@ -0,0 +1,336 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// extern herr_t _go_hdf5_vfd_open_cb(uintptr_t handle);
+// extern herr_t _go_hdf5_vfd_close_cb(uintptr_t handle);
+// extern haddr_t _go_hdf5_vfd_get_eof_cb(uintptr_t handle);
+// extern herr_t _go_hdf5_vfd_read_cb(uintptr_t handle, haddr_t addr, size_t size, void *buf);
+// extern herr_t _go_hdf5_vfd_write_cb(uintptr_t handle, haddr_t addr, size_t size, void *buf);
+//
+// typedef struct {
+//   H5FD_t pub; // Must be first
+//   uintptr_t handle;
+//   haddr_t eoa;
+// } _go_hdf5_vfd_t;
+//
+// static H5FD_t *_go_hdf5_vfd_open(const char *name, unsigned flags, hid_t fapl, haddr_t maxaddr) {
+//   const uintptr_t *handle = (const uintptr_t *)H5Pget_driver_info(fapl);
+//   if (handle == NULL || _go_hdf5_vfd_open_cb(*handle) < 0) {
+//     return NULL;
+//   }
+//   _go_hdf5_vfd_t *f = (_go_hdf5_vfd_t *)calloc(1, sizeof(_go_hdf5_vfd_t));
+//   if (f == NULL) {
+//     _go_hdf5_vfd_close_cb(*handle);
+//     return NULL;
+//   }
+//   f->handle = *handle;
+//   return (H5FD_t *)f;
+// }
+//
+// static herr_t _go_hdf5_vfd_close(H5FD_t *file) {
+//   _go_hdf5_vfd_t *f = (_go_hdf5_vfd_t *)file;
+//   herr_t err = _go_hdf5_vfd_close_cb(f->handle);
+//   free(f);
+//   return err;
+// }
+//
+// static herr_t _go_hdf5_vfd_query(const H5FD_t *file, unsigned long *flags) {
+//   *flags = H5FD_FEAT_AGGREGATE_METADATA | H5FD_FEAT_ACCUMULATE_METADATA | H5FD_FEAT_DATA_SIEVE | H5FD_FEAT_AGGREGATE_SMALLDATA;
+//   return 0;
+// }
+//
+// static haddr_t _go_hdf5_vfd_get_eoa(const H5FD_t *file, H5FD_mem_t type) {
+//   return ((const _go_hdf5_vfd_t *)file)->eoa;
+// }
+//
+// static herr_t _go_hdf5_vfd_set_eoa(H5FD_t *file, H5FD_mem_t type, haddr_t addr) {
+//   ((_go_hdf5_vfd_t *)file)->eoa = addr;
+//   return 0;
+// }
+//
+// static haddr_t _go_hdf5_vfd_get_eof(const H5FD_t *file, H5FD_mem_t type) {
+//   return _go_hdf5_vfd_get_eof_cb(((const _go_hdf5_vfd_t *)file)->handle);
+// }
+//
+// static herr_t _go_hdf5_vfd_read(H5FD_t *file, H5FD_mem_t type, hid_t dxpl, haddr_t addr, size_t size, void *buf) {
+//   return _go_hdf5_vfd_read_cb(((_go_hdf5_vfd_t *)file)->handle, addr, size, buf);
+// }
+//
+// static herr_t _go_hdf5_vfd_write(H5FD_t *file, H5FD_mem_t type, hid_t dxpl, haddr_t addr, size_t size, const void *buf) {
+//   return _go_hdf5_vfd_write_cb(((_go_hdf5_vfd_t *)file)->handle, addr, size, (void *)buf);
+// }
+//
+// static const H5FD_class_t _go_hdf5_vfd_class = {
+// #if H5_VERSION_GE(1,14,0)
+//   .version = H5FD_CLASS_VERSION,
+//   .value = 510,
+// #endif
+//   .name = "go",
+//   .maxaddr = HADDR_MAX,
+//   .fc_degree = H5F_CLOSE_WEAK,
+//   .fapl_size = sizeof(uintptr_t),
+//   .open = _go_hdf5_vfd_open,
+//   .close = _go_hdf5_vfd_close,
+//   .query = _go_hdf5_vfd_query,
+//   .get_eoa = _go_hdf5_vfd_get_eoa,
+//   .set_eoa = _go_hdf5_vfd_set_eoa,
+//   .get_eof = _go_hdf5_vfd_get_eof,
+//   .read = _go_hdf5_vfd_read,
+//   .write = _go_hdf5_vfd_write,
+//   .fl_map = H5FD_FLMAP_DICHOTOMY,
+// };
+//
+// static inline hid_t _go_hdf5_vfd_register(void) {
+//   return H5FDregister(&_go_hdf5_vfd_class);
+// }
+//
+// static inline herr_t _go_hdf5_vfd_set_fapl(hid_t fapl, hid_t driver, uintptr_t handle) {
+//   return H5Pset_driver(fapl, driver, &handle);
+// }
+import "C"
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// ReadWriterAt is the storage of a file created with CreateFileWriterAt.
+// The library reads back the data it wrote, so the storage must implement
+// both io.ReaderAt and io.WriterAt.
+type ReadWriterAt interface {
+	io.ReaderAt
+	io.WriterAt
+}
+
+// OpenFileReaderAt opens the HDF5 file of size bytes read from r. It allows
+// any Go storage, such as HTTP range requests or object stores, to back a
+// read-only file. The reads of the library are done in blocks which are
+// cached, so r sees few and aligned requests. The returned file must be
+// closed by the user when it is no longer needed.
+func OpenFileReaderAt(r io.ReaderAt, size int64) (*File, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("hdf5: invalid file size %d", size)
+	}
+	return openFileVFD(&vfdFile{r: r, size: size}, F_ACC_RDONLY, false)
+}
+
+// CreateFileWriterAt creates an HDF5 file stored in rw, which is expected to
+// be empty. The returned file must be closed by the user when it is no
+// longer needed, after which rw holds the complete file.
+func CreateFileWriterAt(rw ReadWriterAt) (*File, error) {
+	return openFileVFD(&vfdFile{r: rw, w: rw}, F_ACC_TRUNC, true)
+}
+
+var goDriver struct {
+	once sync.Once
+	id   C.hid_t
+	err  error
+}
+
+// goDriverID returns the identifier of the Go virtual file driver,
+// registering it with the library on first use.
+func goDriverID() (C.hid_t, error) {
+	defer lockThread()()
+	goDriver.once.Do(func() {
+		goDriver.id = C._go_hdf5_vfd_register()
+		if err := checkID(goDriver.id); err != nil {
+			goDriver.err = fmt.Errorf("hdf5: could not register Go file driver: %w", err)
+		}
+	})
+	return goDriver.id, goDriver.err
+}
+
+func openFileVFD(vf *vfdFile, flags int, create bool) (*File, error) {
+	defer lockThread()()
+	driver, err := goDriverID()
+	if err != nil {
+		return nil, err
+	}
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		return nil, err
+	}
+	defer fapl.Close()
+
+	// The handle is released by the last of this function and the driver
+	// to be done with the file.
+	vf.blocks = make(map[int64]*list.Element)
+	vf.lru = list.New()
+	vf.refs = 1
+	h := cgo.NewHandle(vf)
+	defer vf.release(h)
+	if err := h5err(C._go_hdf5_vfd_set_fapl(fapl.id, driver, C.uintptr_t(h))); err != nil {
+		return nil, err
+	}
+
+	var f *File
+	if create {
+		f, err = CreateFileWith("go", flags, P_DEFAULT, fapl)
+	} else {
+		f, err = OpenFileWithProp("go", flags, fapl)
+	}
+	if err != nil {
+		if ioErr := vf.lastError(); ioErr != nil {
+			return nil, fmt.Errorf("%w: %w", err, ioErr)
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+const (
+	vfdBlockSize   = 64 << 10 // Size of the blocks read from an io.ReaderAt
+	vfdCacheBlocks = 64       // Number of blocks kept in the cache
+)
+
+// vfdFile is the state of a file opened with the Go virtual file driver.
+type vfdFile struct {
+	mu   sync.Mutex
+	r    io.ReaderAt
+	w    io.WriterAt
+	size int64
+	refs int
+	err  error
+
+	// blocks holds the cached blocks by index, lru holds the
+	// *vfdBlock of the cache from the most to the least recently used.
+	blocks map[int64]*list.Element
+	lru    *list.List
+}
+
+type vfdBlock struct {
+	index int64
+	data  []byte
+}
+
+func (vf *vfdFile) lastError() error {
+	vf.mu.Lock()
+	defer vf.mu.Unlock()
+	return vf.err
+}
+
+func (vf *vfdFile) retain() {
+	vf.mu.Lock()
+	vf.refs++
+	vf.mu.Unlock()
+}
+
+func (vf *vfdFile) release(h cgo.Handle) {
+	vf.mu.Lock()
+	vf.refs--
+	done := vf.refs == 0
+	vf.mu.Unlock()
+	if done {
+		h.Delete()
+	}
+}
+
+func (vf *vfdFile) eof() int64 {
+	vf.mu.Lock()
+	defer vf.mu.Unlock()
+	return vf.size
+}
+
+// readAt fills p with the data at off, going through the block cache.
+// Data beyond the end of the file reads as zeros.
+func (vf *vfdFile) readAt(p []byte, off int64) error {
+	vf.mu.Lock()
+	defer vf.mu.Unlock()
+	for len(p) > 0 {
+		index := off / vfdBlockSize
+		data, err := vf.block(index)
+		if err != nil {
+			vf.err = err
+			return err
+		}
+		start := int(off - index*vfdBlockSize)
+		n := copy(p, data[min(start, len(data)):])
+		if n == 0 {
+			// Past the end of the file.
+			n = min(len(p), vfdBlockSize-start)
+			clear(p[:n])
+		}
+		p = p[n:]
+		off += int64(n)
+	}
+	return nil
+}
+
+// block returns the data of the block at index, reading it if it is not
+// cached. The data of the last block of the file is shorter than
+// vfdBlockSize.
+func (vf *vfdFile) block(index int64) ([]byte, error) {
+	if e, ok := vf.blocks[index]; ok {
+		vf.lru.MoveToFront(e)
+		return e.Value.(*vfdBlock).data, nil
+	}
+
+	off := index * vfdBlockSize
+	n := min(vf.size-off, vfdBlockSize)
+	if n <= 0 {
+		return nil, nil
+	}
+	data := make([]byte, n)
+	if m, err := vf.r.ReadAt(data, off); m < len(data) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("hdf5: could not read %d bytes at offset %d: %w", n, off, err)
+	}
+
+	vf.blocks[index] = vf.lru.PushFront(&vfdBlock{index: index, data: data})
+	if vf.lru.Len() > vfdCacheBlocks {
+		e := vf.lru.Back()
+		vf.lru.Remove(e)
+		delete(vf.blocks, e.Value.(*vfdBlock).index)
+	}
+	return data, nil
+}
+
+// writeAt writes p at off and drops the cached blocks it overlaps.
+func (vf *vfdFile) writeAt(p []byte, off int64) error {
+	vf.mu.Lock()
+	defer vf.mu.Unlock()
+	if vf.w == nil {
+		vf.err = errors.New("hdf5: file is read-only")
+		return vf.err
+	}
+	if _, err := vf.w.WriteAt(p, off); err != nil {
+		vf.err = fmt.Errorf("hdf5: could not write %d bytes at offset %d: %w", len(p), off, err)
+		return vf.err
+	}
+	end := off + int64(len(p))
+	for index := off / vfdBlockSize; index*vfdBlockSize < end; index++ {
+		if e, ok := vf.blocks[index]; ok {
+			vf.lru.Remove(e)
+			delete(vf.blocks, index)
+		}
+	}
+	// The last block may have grown.
+	if e, ok := vf.blocks[vf.size/vfdBlockSize]; ok && end > vf.size {
+		vf.lru.Remove(e)
+		delete(vf.blocks, vf.size/vfdBlockSize)
+	}
+	vf.size = max(vf.size, end)
+	return nil
+}
+
+// vfdBuffer returns the C buffer of a driver read or write as a []byte.
+func vfdBuffer(buf unsafe.Pointer, size C.size_t) []byte {
+	if size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(buf), int(size))
+}
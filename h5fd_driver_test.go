@@ -0,0 +1,167 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// memFile is an in-memory ReadWriterAt.
+type memFile struct {
+	data []byte
+}
+
+func (m *memFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *memFile) WriteAt(p []byte, off int64) (int, error) {
+	if end := off + int64(len(p)); end > int64(len(m.data)) {
+		m.data = append(m.data, make([]byte, end-int64(len(m.data)))...)
+	}
+	return copy(m.data[off:], p), nil
+}
+
+// countingReader records the requests made to an io.ReaderAt.
+type countingReader struct {
+	r       io.ReaderAt
+	offsets []int64
+}
+
+func (c *countingReader) ReadAt(p []byte, off int64) (int, error) {
+	c.offsets = append(c.offsets, off)
+	return c.r.ReadAt(p, off)
+}
+
+func TestFileReaderAtWriterAt(t *testing.T) {
+	var mem memFile
+	f, err := CreateFileWriterAt(&mem)
+	if err != nil {
+		t.Fatalf("CreateFileWriterAt failed: %s", err)
+	}
+	dims := []uint{500, 100}
+	space, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dset, err := f.CreateDataset("data", T_NATIVE_DOUBLE, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]float64, dims[0]*dims[1])
+	for i := range want {
+		want[i] = float64(i) / 3
+	}
+	if err := dset.Write(&want); err != nil {
+		t.Fatal(err)
+	}
+	dset.Close()
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(mem.data, []byte("\x89HDF\r\n\x1a\n")) {
+		t.Fatalf("missing HDF5 signature in written file of %d bytes", len(mem.data))
+	}
+
+	r := &countingReader{r: bytes.NewReader(mem.data)}
+	f, err = OpenFileReaderAt(r, int64(len(mem.data)))
+	if err != nil {
+		t.Fatalf("OpenFileReaderAt failed: %s", err)
+	}
+	defer f.Close()
+	dset, err = f.OpenDataset("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	got := make([]float64, len(want))
+	if err := dset.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("wrong value at %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if len(r.offsets) == 0 {
+		t.Fatal("no reads from the io.ReaderAt")
+	}
+	seen := make(map[int64]bool)
+	for _, off := range r.offsets {
+		if off%vfdBlockSize != 0 {
+			t.Errorf("unaligned read at offset %d", off)
+		}
+		if seen[off] {
+			t.Errorf("block at offset %d read twice", off)
+		}
+		seen[off] = true
+	}
+
+	// Writes fail on a read-only file.
+	if _, err := f.CreateGroup("group"); err == nil {
+		t.Error("expected an error writing to a file opened from an io.ReaderAt")
+	}
+}
+
+type failingReader struct{}
+
+var errFailingRead = errors.New("failing read")
+
+func (failingReader) ReadAt(p []byte, off int64) (int, error) {
+	return 0, errFailingRead
+}
+
+func TestFileReaderAtErrors(t *testing.T) {
+	if _, err := OpenFileReaderAt(bytes.NewReader([]byte("not an HDF5 file")), 16); err == nil {
+		t.Error("expected an error opening an invalid file")
+	}
+	_, err := OpenFileReaderAt(failingReader{}, 1<<20)
+	if !errors.Is(err, errFailingRead) {
+		t.Errorf("expected the error of the io.ReaderAt, got %v", err)
+	}
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fname)
+	g, err := f.CreateGroup("group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Close()
+	f.Close()
+
+	osf, err := os.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer osf.Close()
+	st, err := osf.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err = OpenFileReaderAt(osf, st.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if !f.LinkExists("group") {
+		t.Error(`expected "group" in file opened from an *os.File`)
+	}
+}
@@ -49,7 +49,7 @@ func (typ GType) String() string {
 
 // CommonFG is for methods common to both File and Group
 type CommonFG struct {
-	Identifier
+	Location
 }
 
 // Group is an HDF5 container object. It can contain any Location.
@@ -61,6 +61,7 @@ type Group struct {
 // in the file. The returned group must be closed by the user when it is no
 // longer needed.
 func (g *CommonFG) CreateGroup(name string) (*Group, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -70,7 +71,23 @@ func (g *CommonFG) CreateGroup(name string) (*Group, error) {
 	if err := checkID(hid); err != nil {
 		return nil, err
 	}
-	group := &Group{CommonFG{Identifier{hid}}}
+	group := &Group{CommonFG{Location{Identifier{hid}}}}
+	return group, nil
+}
+
+// CreateGroupWith creates and returns a new empty group with a user-defined
+// group creation PropList. The returned group must be closed by the user when
+// it is no longer needed.
+func (g *CommonFG) CreateGroupWith(name string, gcpl *PropList) (*Group, error) {
+	defer lockThread()()
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Gcreate2(g.id, c_name, P_DEFAULT.id, gcpl.id, P_DEFAULT.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	group := &Group{CommonFG{Location{Identifier{hid}}}}
 	return group, nil
 }
 
@@ -86,28 +103,6 @@ func (g *CommonFG) CreateDatasetWith(name string, dtype *Datatype, dspace *Datas
 	return createDataset(g.id, name, dtype, dspace, dcpl)
 }
 
-// CreateAttribute creates a new attribute at this location. The returned
-// attribute must be closed by the user when it is no longer needed.
-func (g *Group) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
-	return createAttribute(g.id, name, dtype, dspace, P_DEFAULT)
-}
-
-// CreateAttributeWith creates a new attribute at this location with a user-defined
-// PropList. The returned dataset must be closed by the user when it is no longer needed.
-func (g *Group) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
-	return createAttribute(g.id, name, dtype, dspace, acpl)
-}
-
-// Opens an existing attribute. The returned attribute must be closed
-// by the user when it is no longer needed.
-func (g *Group) OpenAttribute(name string) (*Attribute, error) {
-	return openAttribute(g.id, name)
-}
-
-func (g *Group) AttributeExists(name string) bool {
-	return attributeExists(g.id, name)
-}
-
 // Close closes the Group.
 func (g *Group) Close() error {
 	return g.closeWith(h5gclose)
@@ -120,6 +115,7 @@ func h5gclose(id C.hid_t) C.herr_t {
 // OpenGroup opens and returns an existing child group from this Group.
 // The returned group must be closed by the user when it is no longer needed.
 func (g *CommonFG) OpenGroup(name string) (*Group, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -127,13 +123,14 @@ func (g *CommonFG) OpenGroup(name string) (*Group, error) {
 	if err := checkID(hid); err != nil {
 		return nil, err
 	}
-	group := &Group{CommonFG{Identifier{hid}}}
+	group := &Group{CommonFG{Location{Identifier{hid}}}}
 	return group, nil
 }
 
 // OpenDataset opens and returns a named Dataset. The returned
 // dataset must be closed by the user when it is no longer needed.
 func (g *CommonFG) OpenDataset(name string) (*Dataset, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -147,6 +144,7 @@ func (g *CommonFG) OpenDataset(name string) (*Dataset, error) {
 // OpenDatasetWith opens and returns a named Dataset with a user-defined PropList.
 // The returned dataset must be closed by the user when it is no longer needed.
 func (g *CommonFG) OpenDatasetWith(name string, dapl *PropList) (*Dataset, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -159,6 +157,7 @@ func (g *CommonFG) OpenDatasetWith(name string, dapl *PropList) (*Dataset, error
 
 // NumObjects returns the number of objects in the Group.
 func (g *CommonFG) NumObjects() (uint, error) {
+	defer lockThread()()
 	var info C.H5G_info_t
 	err := h5err(C.H5Gget_info(g.id, &info))
 	return uint(info.nlinks), err
@@ -86,7 +86,7 @@ func (i Identifier) File() *File {
 	if fid < 0 {
 		return nil
 	}
-	return &File{CommonFG{Identifier{fid}}}
+	return &File{CommonFG{Location{Identifier{fid}}}}
 }
 
 // Type returns the type of the identifier.
@@ -95,6 +95,7 @@ func (i Identifier) Type() IType {
 }
 
 func (i *Identifier) closeWith(fn func(C.hid_t) C.herr_t) error {
+	defer lockThread()()
 	if i.id == 0 {
 		return nil
 	}
@@ -102,3 +103,9 @@ func (i *Identifier) closeWith(fn func(C.hid_t) C.herr_t) error {
 	i.id = 0
 	return err
 }
+
+// Location is an HDF5 object that attributes can be attached to. It is
+// embedded by File, Group and Dataset.
+type Location struct {
+	Identifier
+}
@@ -0,0 +1,310 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <string.h>
+//
+// extern herr_t _go_hdf5_literate_cb(hid_t group, char *name, void *info, void *data);
+//
+// static inline herr_t _go_hdf5_H5Literate_by_name(hid_t loc_id, const char *group_name, H5_index_t idx_type, uintptr_t data) {
+//   hsize_t idx = 0;
+//   return H5Literate_by_name(loc_id, group_name, idx_type, H5_ITER_INC, &idx, (H5L_iterate_t)_go_hdf5_literate_cb, (void *)data, H5P_DEFAULT);
+// }
+//
+// static inline herr_t _go_hdf5_H5Lget_info(hid_t loc_id, const char *name, H5L_type_t *type, size_t *val_size) {
+//   H5L_info_t info;
+//   herr_t err = H5Lget_info(loc_id, name, &info, H5P_DEFAULT);
+//   if (err < 0) {
+//     return err;
+//   }
+//   *type = info.type;
+//   *val_size = 0;
+//   if (info.type != H5L_TYPE_HARD) {
+//     *val_size = info.u.val_size;
+//   }
+//   return err;
+// }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// LinkType describes the kind of a link inside a Group or File.
+type LinkType C.H5L_type_t
+
+const (
+	L_TYPE_ERROR    LinkType = C.H5L_TYPE_ERROR    // Invalid link type
+	L_TYPE_HARD     LinkType = C.H5L_TYPE_HARD     // Hard link to an object
+	L_TYPE_SOFT     LinkType = C.H5L_TYPE_SOFT     // Soft link to a path
+	L_TYPE_EXTERNAL LinkType = C.H5L_TYPE_EXTERNAL // External link to a path in another file
+)
+
+func (typ LinkType) String() string {
+	switch typ {
+	case L_TYPE_ERROR:
+		return "error"
+	case L_TYPE_HARD:
+		return "hard"
+	case L_TYPE_SOFT:
+		return "soft"
+	case L_TYPE_EXTERNAL:
+		return "external"
+	default:
+		return fmt.Sprintf("LinkType(%d)", int(typ))
+	}
+}
+
+// LinkInfo describes a link. TargetPath is set for soft and external links,
+// and TargetFile is only set for external links.
+type LinkInfo struct {
+	Type       LinkType
+	TargetFile string
+	TargetPath string
+}
+
+// CreateHardLink creates a new hard link called name pointing to the
+// object at target.
+func (g *CommonFG) CreateHardLink(target, name string) error {
+	defer lockThread()()
+	c_target := C.CString(target)
+	defer C.free(unsafe.Pointer(c_target))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	err := h5err(C.H5Lcreate_hard(g.id, c_target, g.id, c_name, P_DEFAULT.id, P_DEFAULT.id))
+	if err != nil {
+		return fmt.Errorf("hdf5: could not create hard link %q -> %q: %w", name, target, err)
+	}
+	return nil
+}
+
+// CreateSoftLink creates a new soft link called name pointing to the path
+// target. The target does not need to exist when the link is created.
+func (g *CommonFG) CreateSoftLink(target, name string) error {
+	defer lockThread()()
+	c_target := C.CString(target)
+	defer C.free(unsafe.Pointer(c_target))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	err := h5err(C.H5Lcreate_soft(c_target, g.id, c_name, P_DEFAULT.id, P_DEFAULT.id))
+	if err != nil {
+		return fmt.Errorf("hdf5: could not create soft link %q -> %q: %w", name, target, err)
+	}
+	return nil
+}
+
+// CreateExternalLink creates a new link called name pointing to the object
+// at path inside the HDF5 file fileName. Neither the file nor the object need
+// to exist when the link is created.
+func (g *CommonFG) CreateExternalLink(fileName, path, name string) error {
+	defer lockThread()()
+	c_file := C.CString(fileName)
+	defer C.free(unsafe.Pointer(c_file))
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	err := h5err(C.H5Lcreate_external(c_file, c_path, g.id, c_name, P_DEFAULT.id, P_DEFAULT.id))
+	if err != nil {
+		return fmt.Errorf("hdf5: could not create external link %q -> %s:%q: %w", name, fileName, path, err)
+	}
+	return nil
+}
+
+// DeleteLink removes the link called name. The object it points to is
+// removed from the file once no other hard link refers to it.
+func (g *CommonFG) DeleteLink(name string) error {
+	defer lockThread()()
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	err := h5err(C.H5Ldelete(g.id, c_name, P_DEFAULT.id))
+	if err != nil {
+		return fmt.Errorf("hdf5: could not delete link %q: %w", name, err)
+	}
+	return nil
+}
+
+// MoveLink renames the link src to dst. Both names are resolved relative to
+// this location, so the link may be moved into another group of the file.
+func (g *CommonFG) MoveLink(src, dst string) error {
+	defer lockThread()()
+	c_src := C.CString(src)
+	defer C.free(unsafe.Pointer(c_src))
+	c_dst := C.CString(dst)
+	defer C.free(unsafe.Pointer(c_dst))
+
+	err := h5err(C.H5Lmove(g.id, c_src, g.id, c_dst, P_DEFAULT.id, P_DEFAULT.id))
+	if err != nil {
+		return fmt.Errorf("hdf5: could not move link %q to %q: %w", src, dst, err)
+	}
+	return nil
+}
+
+// LinkInfo returns information about the link called name. The link itself
+// is inspected, soft and external links are not followed.
+func (g *CommonFG) LinkInfo(name string) (LinkInfo, error) {
+	defer lockThread()()
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var (
+		info  LinkInfo
+		c_typ C.H5L_type_t
+		c_sz  C.size_t
+	)
+	err := h5err(C._go_hdf5_H5Lget_info(g.id, c_name, &c_typ, &c_sz))
+	if err != nil {
+		return info, fmt.Errorf("hdf5: could not get info for link %q: %w", name, err)
+	}
+	info.Type = LinkType(c_typ)
+	if info.Type == L_TYPE_HARD || c_sz == 0 {
+		return info, nil
+	}
+
+	buf := C.malloc(c_sz)
+	defer C.free(buf)
+	err = h5err(C.H5Lget_val(g.id, c_name, buf, c_sz, P_DEFAULT.id))
+	if err != nil {
+		return info, fmt.Errorf("hdf5: could not get value for link %q: %w", name, err)
+	}
+
+	switch info.Type {
+	case L_TYPE_SOFT:
+		info.TargetPath = C.GoString((*C.char)(buf))
+	case L_TYPE_EXTERNAL:
+		var (
+			c_flags C.uint
+			c_file  *C.char
+			c_path  *C.char
+		)
+		err = h5err(C.H5Lunpack_elink_val(buf, c_sz, &c_flags, &c_file, &c_path))
+		if err != nil {
+			return info, fmt.Errorf("hdf5: could not decode external link %q: %w", name, err)
+		}
+		info.TargetFile = C.GoString(c_file)
+		info.TargetPath = C.GoString(c_path)
+	}
+	return info, nil
+}
+
+// IndexType selects the index used to traverse the links of a group.
+type IndexType C.H5_index_t
+
+const (
+	INDEX_NAME      IndexType = C.H5_INDEX_NAME      // Traverse links in alphanumeric order of their names
+	INDEX_CRT_ORDER IndexType = C.H5_INDEX_CRT_ORDER // Traverse links in creation order
+)
+
+var (
+	// SkipGroup can be returned by a WalkFunc called for a group so that the
+	// members of the group are not visited.
+	SkipGroup = errors.New("hdf5: skip this group")
+
+	// StopWalk can be returned by a WalkFunc to end the traversal early.
+	// Walk and Visit then return a nil error.
+	StopWalk = errors.New("hdf5: stop walking")
+)
+
+// WalkFunc is the type of the function called by Walk and Visit for each
+// link below the starting location. The path is relative to the starting
+// location. Soft and external links are reported but not followed; for them
+// info only has its Type set to O_TYPE_UNKNOWN and LinkInfo can be used to
+// find their target.
+//
+// If the function returns SkipGroup for a group, its members are skipped.
+// If it returns StopWalk, the traversal ends without error. Any other
+// non-nil error ends the traversal and is returned.
+type WalkFunc func(path string, info ObjectInfo) error
+
+// Walk recursively visits every link below this location in name order,
+// calling fn for each of them. Groups reachable through several hard links
+// are reported for each link but only descended into once.
+func (g *CommonFG) Walk(fn WalkFunc) error {
+	return g.Visit(INDEX_NAME, fn)
+}
+
+// Visit is like Walk but traverses the links of every group with the given
+// index. Traversing in INDEX_CRT_ORDER requires groups created with link
+// creation order tracking, see PropList.SetLinkCreationOrder.
+func (g *CommonFG) Visit(index IndexType, fn WalkFunc) error {
+	visited := make(map[uint64]bool)
+	if info, err := g.Info(); err == nil {
+		visited[info.Address] = true
+	}
+	err := g.visit(".", index, visited, fn)
+	if err == StopWalk {
+		return nil
+	}
+	return err
+}
+
+func (g *CommonFG) visit(grp string, index IndexType, visited map[uint64]bool, fn WalkFunc) error {
+	names, err := g.linkNames(grp, index)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		p := path.Join(grp, name)
+		link, err := g.LinkInfo(p)
+		if err != nil {
+			return err
+		}
+		if link.Type != L_TYPE_HARD {
+			if err := fn(p, ObjectInfo{Type: O_TYPE_UNKNOWN}); err != nil && err != SkipGroup {
+				return err
+			}
+			continue
+		}
+
+		info, err := g.ObjectInfoByName(p)
+		if err != nil {
+			return err
+		}
+		switch err := fn(p, info); err {
+		case nil:
+		case SkipGroup:
+			continue
+		default:
+			return err
+		}
+		if info.Type != O_TYPE_GROUP || visited[info.Address] {
+			continue
+		}
+		visited[info.Address] = true
+		if err := g.visit(p, index, visited, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkNames returns the names of the links in the group grp, relative to
+// this location, in the order of the given index.
+func (g *CommonFG) linkNames(grp string, index IndexType) ([]string, error) {
+	defer lockThread()()
+	c_grp := C.CString(grp)
+	defer C.free(unsafe.Pointer(c_grp))
+
+	var names []string
+	h := cgo.NewHandle(&names)
+	defer h.Delete()
+
+	err := h5err(C._go_hdf5_H5Literate_by_name(g.id, c_grp, C.H5_index_t(index), C.uintptr_t(h)))
+	if err != nil {
+		return nil, fmt.Errorf("hdf5: could not iterate over links of %q: %w", grp, err)
+	}
+	return names, nil
+}
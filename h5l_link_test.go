@@ -0,0 +1,205 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(fname)
+	defer f.Close()
+
+	g, err := f.CreateGroup("results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	if err = f.CreateHardLink("/results", "alias"); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.CreateSoftLink("/results", "soft"); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.CreateExternalLink("other.h5", "/data", "ext"); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.CreateHardLink("/does/not/exist", "bad"); err == nil {
+		t.Error("expected an error creating a hard link to a missing object")
+	}
+
+	for _, tc := range []struct {
+		name string
+		want LinkInfo
+	}{
+		{"results", LinkInfo{Type: L_TYPE_HARD}},
+		{"alias", LinkInfo{Type: L_TYPE_HARD}},
+		{"soft", LinkInfo{Type: L_TYPE_SOFT, TargetPath: "/results"}},
+		{"ext", LinkInfo{Type: L_TYPE_EXTERNAL, TargetFile: "other.h5", TargetPath: "/data"}},
+	} {
+		got, err := f.LinkInfo(tc.name)
+		if err != nil {
+			t.Errorf("LinkInfo(%q) failed: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("LinkInfo(%q): got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	alias, err := f.OpenGroup("alias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alias.Close()
+
+	if err = f.MoveLink("alias", "/results/self"); err != nil {
+		t.Fatal(err)
+	}
+	if f.LinkExists("alias") {
+		t.Error(`unexpected "alias" link after move`)
+	}
+	if !g.LinkExists("self") {
+		t.Error(`expected "self" link after move`)
+	}
+
+	if err = f.DeleteLink("soft"); err != nil {
+		t.Fatal(err)
+	}
+	if f.LinkExists("soft") {
+		t.Error(`unexpected "soft" link after delete`)
+	}
+	if err = f.DeleteLink("soft"); err == nil {
+		t.Error("expected an error deleting a missing link")
+	}
+	if _, err = f.LinkInfo("soft"); err == nil {
+		t.Error("expected an error for info on a missing link")
+	}
+}
+
+func TestWalk(t *testing.T) {
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(fname)
+	defer f.Close()
+
+	gcpl, err := NewPropList(P_GROUP_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gcpl.Close()
+	if err = gcpl.SetLinkCreationOrder(true, true); err != nil {
+		t.Fatal(err)
+	}
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scalar.Close()
+
+	root, err := f.CreateGroupWith("root", gcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	for _, name := range []string{"zeta", "alpha"} {
+		g, err := root.CreateGroupWith(name, gcpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dset, err := g.CreateDataset("dset", T_NATIVE_INT32, scalar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dset.Close()
+		g.Close()
+	}
+	// A soft link cycle must not be followed.
+	if err = root.CreateSoftLink("/root", "zeta/loop"); err != nil {
+		t.Fatal(err)
+	}
+
+	walk := func(index IndexType, skip, stop string) ([]string, error) {
+		var paths []string
+		err := root.Visit(index, func(path string, info ObjectInfo) error {
+			paths = append(paths, path+":"+info.Type.String())
+			switch path {
+			case skip:
+				return SkipGroup
+			case stop:
+				return StopWalk
+			}
+			return nil
+		})
+		return paths, err
+	}
+
+	for _, tc := range []struct {
+		name       string
+		index      IndexType
+		skip, stop string
+		want       []string
+	}{
+		{
+			name:  "name order",
+			index: INDEX_NAME,
+			want: []string{
+				"alpha:group", "alpha/dset:dataset",
+				"zeta:group", "zeta/dset:dataset", "zeta/loop:unknown",
+			},
+		},
+		{
+			name:  "creation order",
+			index: INDEX_CRT_ORDER,
+			want: []string{
+				"zeta:group", "zeta/dset:dataset", "zeta/loop:unknown",
+				"alpha:group", "alpha/dset:dataset",
+			},
+		},
+		{
+			name:  "skip group",
+			index: INDEX_NAME,
+			skip:  "alpha",
+			want:  []string{"alpha:group", "zeta:group", "zeta/dset:dataset", "zeta/loop:unknown"},
+		},
+		{
+			name:  "stop",
+			index: INDEX_NAME,
+			stop:  "alpha/dset",
+			want:  []string{"alpha:group", "alpha/dset:dataset"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := walk(tc.index, tc.skip, tc.stop)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("wrong traversal:\ngot= %q\nwant=%q", got, tc.want)
+			}
+		})
+	}
+
+	want := errors.New("boom")
+	if err = f.Walk(func(string, ObjectInfo) error { return want }); err != want {
+		t.Errorf("wrong error from Walk: got %v, want %v", err, want)
+	}
+
+	// The root group of the file does not track creation order.
+	if err = f.Visit(INDEX_CRT_ORDER, func(string, ObjectInfo) error { return nil }); err == nil {
+		t.Error("expected an error visiting an untracked group in creation order")
+	}
+}
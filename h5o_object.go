@@ -0,0 +1,135 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+//
+// #if H5_VERSION_GE(1, 12, 0)
+// typedef H5O_info1_t _go_hdf5_H5O_info_t;
+// #else
+// typedef H5O_info_t _go_hdf5_H5O_info_t;
+// #endif
+//
+// static const unsigned _go_hdf5_H5O_INFO_FIELDS = H5O_INFO_BASIC | H5O_INFO_TIME | H5O_INFO_NUM_ATTRS;
+//
+// static inline herr_t _go_hdf5_H5Oget_info(hid_t loc_id, _go_hdf5_H5O_info_t *oinfo) {
+//   return H5Oget_info2(loc_id, oinfo, _go_hdf5_H5O_INFO_FIELDS);
+// }
+//
+// static inline herr_t _go_hdf5_H5Oget_info_by_name(hid_t loc_id, const char *name, _go_hdf5_H5O_info_t *oinfo) {
+//   return H5Oget_info_by_name2(loc_id, name, oinfo, _go_hdf5_H5O_INFO_FIELDS, H5P_DEFAULT);
+// }
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// ObjectType describes the type of an HDF5 object.
+type ObjectType C.H5O_type_t
+
+const (
+	O_TYPE_UNKNOWN        ObjectType = C.H5O_TYPE_UNKNOWN        // Unknown object type
+	O_TYPE_GROUP          ObjectType = C.H5O_TYPE_GROUP          // Object is a group
+	O_TYPE_DATASET        ObjectType = C.H5O_TYPE_DATASET        // Object is a dataset
+	O_TYPE_NAMED_DATATYPE ObjectType = C.H5O_TYPE_NAMED_DATATYPE // Object is a committed datatype
+)
+
+func (typ ObjectType) String() string {
+	switch typ {
+	case O_TYPE_UNKNOWN:
+		return "unknown"
+	case O_TYPE_GROUP:
+		return "group"
+	case O_TYPE_DATASET:
+		return "dataset"
+	case O_TYPE_NAMED_DATATYPE:
+		return "named datatype"
+	default:
+		return fmt.Sprintf("ObjectType(%d)", int(typ))
+	}
+}
+
+// ObjectInfo holds the metadata of an HDF5 object. Timestamps are the zero
+// time.Time unless time tracking was enabled when the object was created.
+type ObjectInfo struct {
+	Type       ObjectType
+	FileNo     uint64    // Number of the file the object is in
+	Address    uint64    // Address of the object header in the file
+	RefCount   uint      // Number of hard links to the object
+	NumAttrs   uint      // Number of attributes attached to the object
+	AccessTime time.Time // Last access time
+	ModTime    time.Time // Last modification time of the raw data
+	ChangeTime time.Time // Last modification time of the metadata
+	BirthTime  time.Time // Creation time
+}
+
+// Verify that the object types implement Object.
+var (
+	_ Object = (*File)(nil)
+	_ Object = (*Group)(nil)
+	_ Object = (*Dataset)(nil)
+	_ Object = (*Datatype)(nil)
+)
+
+func newObjectInfo(c *C._go_hdf5_H5O_info_t) ObjectInfo {
+	return ObjectInfo{
+		Type:       ObjectType(c._type),
+		FileNo:     uint64(c.fileno),
+		Address:    uint64(c.addr),
+		RefCount:   uint(c.rc),
+		NumAttrs:   uint(c.num_attrs),
+		AccessTime: unixTime(c.atime),
+		ModTime:    unixTime(c.mtime),
+		ChangeTime: unixTime(c.ctime),
+		BirthTime:  unixTime(c.btime),
+	}
+}
+
+func unixTime(t C.time_t) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(t), 0)
+}
+
+func objectInfo(id C.hid_t) (ObjectInfo, error) {
+	defer lockThread()()
+	var c_info C._go_hdf5_H5O_info_t
+	if err := h5err(C._go_hdf5_H5Oget_info(id, &c_info)); err != nil {
+		return ObjectInfo{}, err
+	}
+	return newObjectInfo(&c_info), nil
+}
+
+// ObjectInfoByName returns the metadata of the object at path, relative
+// to this location.
+func (g *CommonFG) ObjectInfoByName(path string) (ObjectInfo, error) {
+	defer lockThread()()
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	var c_info C._go_hdf5_H5O_info_t
+	if err := h5err(C._go_hdf5_H5Oget_info_by_name(g.id, c_path, &c_info)); err != nil {
+		return ObjectInfo{}, fmt.Errorf("hdf5: could not get info for object %q: %w", path, err)
+	}
+	return newObjectInfo(&c_info), nil
+}
+
+// Info returns the metadata of the object at this location. For a File
+// it describes the root group.
+func (l *Location) Info() (ObjectInfo, error) {
+	return objectInfo(l.id)
+}
+
+// Info returns the metadata of a committed Datatype. It fails for
+// transient datatypes, which are not stored in a file.
+func (t *Datatype) Info() (ObjectInfo, error) {
+	return objectInfo(t.id)
+}
@@ -0,0 +1,100 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"os"
+	"testing"
+)
+
+func TestObjectInfo(t *testing.T) {
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(fname)
+	defer f.Close()
+
+	g, err := f.CreateGroup("grp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scalar.Close()
+
+	dset, err := g.CreateDataset("dset", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+
+	attr, err := dset.CreateAttribute("units", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attr.Close()
+
+	if err = f.CreateHardLink("/grp/dset", "alias"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range []Object{f, g, dset} {
+		info, err := obj.Info()
+		if err != nil {
+			t.Fatalf("Info() for %q failed: %v", obj.Name(), err)
+		}
+		if info.Address == 0 {
+			t.Errorf("unexpected zero address for %q", obj.Name())
+		}
+	}
+
+	ginfo, err := g.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ginfo.Type != O_TYPE_GROUP {
+		t.Errorf("wrong type for group: got %v, want %v", ginfo.Type, O_TYPE_GROUP)
+	}
+
+	dinfo, err := dset.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dinfo.Type != O_TYPE_DATASET {
+		t.Errorf("wrong type for dataset: got %v, want %v", dinfo.Type, O_TYPE_DATASET)
+	}
+	if dinfo.RefCount != 2 {
+		t.Errorf("wrong reference count for dataset: got %d, want 2", dinfo.RefCount)
+	}
+	if dinfo.NumAttrs != 1 {
+		t.Errorf("wrong number of attributes for dataset: got %d, want 1", dinfo.NumAttrs)
+	}
+
+	ainfo, err := f.ObjectInfoByName("alias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ainfo.Address != dinfo.Address {
+		t.Errorf("hard link resolved to a different object: got %d, want %d", ainfo.Address, dinfo.Address)
+	}
+
+	if _, err = f.ObjectInfoByName("missing"); err == nil {
+		t.Error("expected an error for a missing object")
+	}
+
+	dtype, err := T_NATIVE_INT32.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	if _, err = dtype.Info(); err == nil {
+		t.Error("expected an error for a transient datatype")
+	}
+}
@@ -11,6 +11,8 @@ package hdf5
 // static inline hid_t _go_hdf5_H5P_DATASET_CREATE() { return H5P_DATASET_CREATE; }
 // static inline hid_t _go_hdf5_H5P_DATASET_ACCESS() { return H5P_DATASET_ACCESS; }
 // static inline hid_t _go_hdf5_H5P_FILE_ACCESS() { return H5P_FILE_ACCESS; }
+// static inline hid_t _go_hdf5_H5P_FILE_CREATE() { return H5P_FILE_CREATE; }
+// static inline hid_t _go_hdf5_H5P_GROUP_CREATE() { return H5P_GROUP_CREATE; }
 // static inline H5FD_ros3_fapl_t _go_hdf5_H5FD_ROS3(int version, bool auth,char *region, char *keyid, char *secretkey){
 //   H5FD_ros3_fapl_t ros3_fa = {
 //     version,
@@ -26,6 +28,7 @@ import "C"
 import (
 	"compress/zlib"
 	"fmt"
+	"reflect"
 	"unsafe"
 )
 
@@ -47,6 +50,8 @@ var (
 	P_DATASET_CREATE PropType  = PropType(C._go_hdf5_H5P_DATASET_CREATE()) // Properties for dataset creation
 	P_DATASET_ACCESS PropType  = PropType(C._go_hdf5_H5P_DATASET_ACCESS()) // Properties for dataset access
 	P_FILE_ACCESS    PropType  = PropType(C._go_hdf5_H5P_FILE_ACCESS())    // Properties for file access
+	P_FILE_CREATE    PropType  = PropType(C._go_hdf5_H5P_FILE_CREATE())    // Properties for file creation
+	P_GROUP_CREATE   PropType  = PropType(C._go_hdf5_H5P_GROUP_CREATE())   // Properties for group creation
 )
 
 func newPropList(id C.hid_t) *PropList {
@@ -56,6 +61,7 @@ func newPropList(id C.hid_t) *PropList {
 // NewPropList creates a new PropList as an instance of a property list class.
 // The returned proplist must be closed by the user when it is no longer needed.
 func NewPropList(cls_id PropType) (*PropList, error) {
+	defer lockThread()()
 	hid := C.H5Pcreate(C.hid_t(cls_id))
 	if err := checkID(hid); err != nil {
 		return nil, err
@@ -71,6 +77,7 @@ func (p *PropList) Close() error {
 // SetChunk sets the size of the chunks used to store a chunked layout dataset.
 // https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetChunk
 func (p *PropList) SetChunk(dims []uint) error {
+	defer lockThread()()
 	ndims := len(dims)
 	if ndims <= 0 {
 		return fmt.Errorf("number of dimensions must be same size as the rank of the dataset, but zero received")
@@ -85,6 +92,7 @@ func (p *PropList) SetChunk(dims []uint) error {
 // GetChunk retrieves the size of chunks for the raw data of a chunked layout dataset.
 // https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetChunk
 func (p *PropList) GetChunk(ndims int) (dims []uint, err error) {
+	defer lockThread()()
 	if ndims <= 0 {
 		err = fmt.Errorf("number of dimensions must be same size as the rank of the dataset, but nonpositive value received")
 		return
@@ -104,22 +112,246 @@ func (p *PropList) GetChunk(ndims int) (dims []uint, err error) {
 // If level is set as DefaultCompression, 6 will be used.
 // https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetDeflate
 func (p *PropList) SetDeflate(level int) error {
+	defer lockThread()()
 	if level == DefaultCompression {
 		level = 6
 	}
 	return h5err(C.H5Pset_deflate(C.hid_t(p.id), C.uint(level)))
 }
 
+// SetShuffle adds the shuffle filter to the pipeline. Shuffling groups the
+// bytes of the elements by significance and improves the compression of the
+// following filters.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetShuffle
+func (p *PropList) SetShuffle() error {
+	defer lockThread()()
+	return h5err(C.H5Pset_shuffle(C.hid_t(p.id)))
+}
+
+// SetFletcher32 adds the Fletcher32 checksum filter to the pipeline.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFletcher32
+func (p *PropList) SetFletcher32() error {
+	defer lockThread()()
+	return h5err(C.H5Pset_fletcher32(C.hid_t(p.id)))
+}
+
+// SetNbit adds the N-bit filter to the pipeline. It packs the significant
+// bits of the elements, as set with Datatype.Precision and Offset.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetNbit
+func (p *PropList) SetNbit() error {
+	defer lockThread()()
+	return h5err(C.H5Pset_nbit(C.hid_t(p.id)))
+}
+
+// SetScaleOffset adds the scale-offset filter to the pipeline. For
+// Z_SO_FLOAT_DSCALE, factor is the number of decimal digits kept. For
+// Z_SO_INT, factor is the minimum number of bits kept, or
+// Z_SO_INT_MINBITS_DEFAULT.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetScaleoffset
+func (p *PropList) SetScaleOffset(scaleType ScaleType, factor int) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_scaleoffset(C.hid_t(p.id), C.H5Z_SO_scale_type_t(scaleType), C.int(factor)))
+}
+
+// SetSzip adds the szip compression filter to the pipeline. The optionsMask
+// is SZIP_EC_OPTION_MASK or SZIP_NN_OPTION_MASK and pixelsPerBlock must be
+// even and at most 32.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetSzip
+func (p *PropList) SetSzip(optionsMask, pixelsPerBlock uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_szip(C.hid_t(p.id), C.uint(optionsMask), C.uint(pixelsPerBlock)))
+}
+
+// SetFilter adds the filter id to the pipeline, with the flags
+// Z_FLAG_MANDATORY or Z_FLAG_OPTIONAL and the auxiliary data cdValues.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFilter
+func (p *PropList) SetFilter(id FilterID, flags uint, cdValues []uint) error {
+	defer lockThread()()
+	c_values := make([]C.uint, len(cdValues)+1)
+	for i, v := range cdValues {
+		c_values[i] = C.uint(v)
+	}
+	return h5err(C.H5Pset_filter(C.hid_t(p.id), C.H5Z_filter_t(id), C.uint(flags), C.size_t(len(cdValues)), &c_values[0]))
+}
+
+// NFilters returns the number of filters in the pipeline.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetNFilters
+func (p *PropList) NFilters() (int, error) {
+	defer lockThread()()
+	n := C.H5Pget_nfilters(C.hid_t(p.id))
+	if err := h5err(C.herr_t(n)); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// Filter returns information about the filter at index i of the pipeline.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetFilter2
+func (p *PropList) Filter(i int) (FilterInfo, error) {
+	defer lockThread()()
+	var (
+		info     FilterInfo
+		c_flags  C.uint
+		c_config C.uint
+		c_name   [256]C.char
+		c_values = make([]C.uint, 8)
+	)
+	for {
+		c_n := C.size_t(len(c_values))
+		id := C.H5Pget_filter2(C.hid_t(p.id), C.uint(i), &c_flags, &c_n, &c_values[0], C.size_t(len(c_name)), &c_name[0], nil)
+		if err := h5err(C.herr_t(id)); err != nil {
+			return info, fmt.Errorf("hdf5: could not get filter %d: %w", i, err)
+		}
+		if int(c_n) > len(c_values) {
+			c_values = make([]C.uint, c_n)
+			continue
+		}
+
+		info.ID = FilterID(id)
+		info.Name = C.GoString(&c_name[0])
+		info.Flags = uint(c_flags)
+		info.CDValues = make([]uint, c_n)
+		for j := range info.CDValues {
+			info.CDValues[j] = uint(c_values[j])
+		}
+
+		// The configuration of filters that are not available is unknown.
+		if ok, _ := FilterAvailable(info.ID); ok && C.H5Zget_filter_info(id, &c_config) >= 0 {
+			info.EncodeEnabled = c_config&C.H5Z_FILTER_CONFIG_ENCODE_ENABLED != 0
+			info.DecodeEnabled = c_config&C.H5Z_FILTER_CONFIG_DECODE_ENABLED != 0
+		}
+		return info, nil
+	}
+}
+
+// SetLayout sets the storage layout of the raw data of a dataset. Setting
+// a chunk size with SetChunk also sets the D_CHUNKED layout.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetLayout
+func (p *PropList) SetLayout(layout Layout) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_layout(C.hid_t(p.id), C.H5D_layout_t(layout)))
+}
+
+// Layout returns the storage layout of the raw data of a dataset.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetLayout
+func (p *PropList) Layout() (Layout, error) {
+	defer lockThread()()
+	layout := Layout(C.H5Pget_layout(C.hid_t(p.id)))
+	if layout == D_LAYOUT_ERROR {
+		return layout, newErrorStack(int(layout))
+	}
+	return layout, nil
+}
+
+// SetFillValue sets the value of the elements of a dataset that are never
+// written. The value is a Go value, or a pointer to one, whose memory
+// representation is described by dtype; it is converted to the datatype of
+// the dataset when the dataset is created. A nil value leaves the fill value
+// undefined.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFillValue
+func (p *PropList) SetFillValue(dtype *Datatype, value interface{}) error {
+	defer lockThread()()
+	if value == nil {
+		return h5err(C.H5Pset_fill_value(C.hid_t(p.id), dtype.id, nil))
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	if err := checkFillValue(dtype, v); err != nil {
+		return err
+	}
+	return h5err(C.H5Pset_fill_value(C.hid_t(p.id), dtype.id, unsafe.Pointer(v.Pointer())))
+}
+
+// FillValue reads the fill value into value, which must be a pointer to
+// a Go value whose memory representation is described by dtype.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetFillValue
+func (p *PropList) FillValue(dtype *Datatype, value interface{}) error {
+	defer lockThread()()
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("hdf5: fill value needs a non-nil pointer, got %T", value)
+	}
+	if err := checkFillValue(dtype, v); err != nil {
+		return err
+	}
+	return h5err(C.H5Pget_fill_value(C.hid_t(p.id), dtype.id, unsafe.Pointer(v.Pointer())))
+}
+
+// checkFillValue returns an error if the value pointed to by v holds Go
+// pointers, which may not be passed to the library, or does not have the
+// size of dtype.
+func checkFillValue(dtype *Datatype, v reflect.Value) error {
+	if hasGoPointers(v.Type().Elem()) {
+		return fmt.Errorf("hdf5: fill value of type %v holds Go pointers: %w", v.Type().Elem(), ErrBadType)
+	}
+	if size := v.Type().Elem().Size(); size != uintptr(dtype.Size()) {
+		return fmt.Errorf("hdf5: fill value of type %v has size %d, datatype has size %d", v.Type().Elem(), size, dtype.Size())
+	}
+	return nil
+}
+
+// FillValueDefined returns whether the fill value is undefined, the default
+// of the library or set with SetFillValue.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-FillValueDefined
+func (p *PropList) FillValueDefined() (FillValueStatus, error) {
+	defer lockThread()()
+	var status C.H5D_fill_value_t
+	err := h5err(C.H5Pfill_value_defined(C.hid_t(p.id), &status))
+	return FillValueStatus(status), err
+}
+
+// SetAllocTime sets the time at which the storage space of a dataset is
+// allocated.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetAllocTime
+func (p *PropList) SetAllocTime(t AllocTime) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_alloc_time(C.hid_t(p.id), C.H5D_alloc_time_t(t)))
+}
+
+// AllocTime returns the time at which the storage space of a dataset is
+// allocated.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetAllocTime
+func (p *PropList) AllocTime() (AllocTime, error) {
+	defer lockThread()()
+	var t C.H5D_alloc_time_t
+	err := h5err(C.H5Pget_alloc_time(C.hid_t(p.id), &t))
+	return AllocTime(t), err
+}
+
+// SetFillTime sets the time at which the fill value is written to the
+// storage space of a dataset.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFillTime
+func (p *PropList) SetFillTime(t FillTime) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_fill_time(C.hid_t(p.id), C.H5D_fill_time_t(t)))
+}
+
+// FillTime returns the time at which the fill value is written to the
+// storage space of a dataset.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetFillTime
+func (p *PropList) FillTime() (FillTime, error) {
+	defer lockThread()()
+	var t C.H5D_fill_time_t
+	err := h5err(C.H5Pget_fill_time(C.hid_t(p.id), &t))
+	return FillTime(t), err
+}
+
 // SetChunkCache sets the raw data chunk cache parameters.
 // To reset them as default, use `D_CHUNK_CACHE_NSLOTS_DEFAULT`, `D_CHUNK_CACHE_NBYTES_DEFAULT` and `D_CHUNK_CACHE_W0_DEFAULT`.
 // https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetChunkCache
 func (p *PropList) SetChunkCache(nslots, nbytes int, w0 float64) error {
+	defer lockThread()()
 	return h5err(C.H5Pset_chunk_cache(C.hid_t(p.id), C.size_t(nslots), C.size_t(nbytes), C.double(w0)))
 }
 
 // GetChunkCache retrieves the number of chunk slots in the raw data chunk cache hash table.
 // https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetChunkCache
 func (p *PropList) GetChunkCache() (nslots, nbytes int, w0 float64, err error) {
+	defer lockThread()()
 	var (
 		c_nslots C.size_t
 		c_nbytes C.size_t
@@ -129,12 +361,182 @@ func (p *PropList) GetChunkCache() (nslots, nbytes int, w0 float64, err error) {
 	return int(c_nslots), int(c_nbytes), float64(c_w0), err
 }
 
+// SetLinkCreationOrder sets whether the creation order of links in a group
+// is tracked and indexed. Tracking is required to iterate over links in
+// creation order, and indexing requires tracking.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetLinkCreationOrder
+func (p *PropList) SetLinkCreationOrder(tracked, indexed bool) error {
+	defer lockThread()()
+	var flags C.uint
+	if tracked {
+		flags |= C.H5P_CRT_ORDER_TRACKED
+	}
+	if indexed {
+		flags |= C.H5P_CRT_ORDER_INDEXED
+	}
+	return h5err(C.H5Pset_link_creation_order(C.hid_t(p.id), flags))
+}
+
+// SetUserblock sets the size of the user block at the beginning of a file,
+// which is ignored by the library. The size must be 0 or a power of two of
+// at least 512 bytes.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetUserblock
+func (p *PropList) SetUserblock(size uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_userblock(C.hid_t(p.id), C.hsize_t(size)))
+}
+
+// Userblock returns the size of the user block of a file.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetUserblock
+func (p *PropList) Userblock() (uint, error) {
+	defer lockThread()()
+	var size C.hsize_t
+	err := h5err(C.H5Pget_userblock(C.hid_t(p.id), &size))
+	return uint(size), err
+}
+
+// SetSizes sets the number of bytes used to store addresses and sizes of
+// objects in a file. Each is 2, 4, 8, 16 or 32, or 0 for the native size.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetSizes
+func (p *PropList) SetSizes(sizeofAddr, sizeofSize uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_sizes(C.hid_t(p.id), C.size_t(sizeofAddr), C.size_t(sizeofSize)))
+}
+
+// Sizes returns the number of bytes used to store addresses and sizes of
+// objects in a file.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetSizes
+func (p *PropList) Sizes() (sizeofAddr, sizeofSize uint, err error) {
+	defer lockThread()()
+	var c_addr, c_size C.size_t
+	err = h5err(C.H5Pget_sizes(C.hid_t(p.id), &c_addr, &c_size))
+	return uint(c_addr), uint(c_size), err
+}
+
+// SetSymK sets half the rank of the B-trees indexing the links of groups,
+// ik, and half the number of links in a leaf node of the symbol tables, lk.
+// A value of 0 leaves the setting unchanged.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetSymK
+func (p *PropList) SetSymK(ik, lk uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_sym_k(C.hid_t(p.id), C.uint(ik), C.uint(lk)))
+}
+
+// SymK returns the symbol table parameters of a file, see SetSymK.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetSymK
+func (p *PropList) SymK() (ik, lk uint, err error) {
+	defer lockThread()()
+	var c_ik, c_lk C.uint
+	err = h5err(C.H5Pget_sym_k(C.hid_t(p.id), &c_ik, &c_lk))
+	return uint(c_ik), uint(c_lk), err
+}
+
+// SetIstoreK sets half the rank of the B-trees indexing the chunks of
+// chunked datasets.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetIstoreK
+func (p *PropList) SetIstoreK(ik uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_istore_k(C.hid_t(p.id), C.uint(ik)))
+}
+
+// IstoreK returns half the rank of the B-trees indexing the chunks of
+// chunked datasets.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetIstoreK
+func (p *PropList) IstoreK() (uint, error) {
+	defer lockThread()()
+	var c_ik C.uint
+	err := h5err(C.H5Pget_istore_k(C.hid_t(p.id), &c_ik))
+	return uint(c_ik), err
+}
+
+// SetLibverBounds sets the range of library versions whose object formats
+// may be used when creating objects in a file. Setting low to a later
+// version than F_LIBVER_EARLIEST may make files unreadable by older versions
+// of the library.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetLibverBounds
+func (p *PropList) SetLibverBounds(low, high LibverBound) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_libver_bounds(C.hid_t(p.id), C.H5F_libver_t(low), C.H5F_libver_t(high)))
+}
+
+// LibverBounds returns the range of library versions whose object formats
+// may be used when creating objects in a file.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetLibverBounds
+func (p *PropList) LibverBounds() (low, high LibverBound, err error) {
+	defer lockThread()()
+	var c_low, c_high C.H5F_libver_t
+	err = h5err(C.H5Pget_libver_bounds(C.hid_t(p.id), &c_low, &c_high))
+	return LibverBound(c_low), LibverBound(c_high), err
+}
+
+// SetFileSpaceStrategy sets the strategy used to manage the free space of
+// a file, whether free space is tracked across file closing and reopening,
+// and the minimum size of the free sections that are tracked.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFileSpaceStrategy
+func (p *PropList) SetFileSpaceStrategy(strategy FileSpaceStrategy, persist bool, threshold uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_file_space_strategy(C.hid_t(p.id), C.H5F_fspace_strategy_t(strategy), C.hbool_t(persist), C.hsize_t(threshold)))
+}
+
+// FileSpaceStrategy returns the free-space management settings of a file,
+// see SetFileSpaceStrategy.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetFileSpaceStrategy
+func (p *PropList) FileSpaceStrategy() (strategy FileSpaceStrategy, persist bool, threshold uint, err error) {
+	defer lockThread()()
+	var (
+		c_strategy  C.H5F_fspace_strategy_t
+		c_persist   C.hbool_t
+		c_threshold C.hsize_t
+	)
+	err = h5err(C.H5Pget_file_space_strategy(C.hid_t(p.id), &c_strategy, &c_persist, &c_threshold))
+	return FileSpaceStrategy(c_strategy), bool(c_persist), uint(c_threshold), err
+}
+
+// SetFileSpacePageSize sets the size of the pages of a file using the
+// F_FSPACE_STRATEGY_PAGE strategy. The size must be at least 512 bytes.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFileSpacePageSize
+func (p *PropList) SetFileSpacePageSize(size uint) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_file_space_page_size(C.hid_t(p.id), C.hsize_t(size)))
+}
+
+// FileSpacePageSize returns the size of the pages of a file.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-GetFileSpacePageSize
+func (p *PropList) FileSpacePageSize() (uint, error) {
+	defer lockThread()()
+	var size C.hsize_t
+	err := h5err(C.H5Pget_file_space_page_size(C.hid_t(p.id), &size))
+	return uint(size), err
+}
+
+// SetFaplCore sets the file access property list to use the core driver,
+// which keeps the whole file in memory. The memory grows by increment bytes
+// as needed. If backingStore is true, the file is written to disk when it is
+// closed; otherwise its content is lost unless retrieved with File.Image.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFaplCore
+func (p *PropList) SetFaplCore(increment uint, backingStore bool) error {
+	defer lockThread()()
+	return h5err(C.H5Pset_fapl_core(C.hid_t(p.id), C.size_t(increment), C.hbool_t(backingStore)))
+}
+
+// SetFileImage sets the initial image of a file opened with the core driver.
+// The image is copied into the property list.
+// https://support.hdfgroup.org/HDF5/doc/RM/RM_H5P.html#Property-SetFileImage
+func (p *PropList) SetFileImage(image []byte) error {
+	defer lockThread()()
+	if len(image) == 0 {
+		return h5err(C.H5Pset_file_image(C.hid_t(p.id), nil, 0))
+	}
+	return h5err(C.H5Pset_file_image(C.hid_t(p.id), unsafe.Pointer(&image[0]), C.size_t(len(image))))
+}
+
 func h5pclose(id C.hid_t) C.herr_t {
 	return C.H5Pclose(id)
 }
 
 // Copy copies an existing PropList to create a new PropList.
 func (p *PropList) Copy() (*PropList, error) {
+	defer lockThread()()
 	hid := C.H5Pcopy(p.id)
 	if err := checkID(hid); err != nil {
 		return nil, err
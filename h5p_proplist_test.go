@@ -5,6 +5,7 @@
 package hdf5
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -173,6 +174,268 @@ func TestChunkCache(t *testing.T) {
 	}
 }
 
+func TestFilters(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+	var (
+		fn    = "test_filters.h5"
+		dsn   = "dset_filters"
+		dims  = []uint{1000, 1000}
+		cdims = []uint{100, 100}
+	)
+	defer os.Remove(fn)
+
+	for _, id := range []FilterID{Z_FILTER_DEFLATE, Z_FILTER_SHUFFLE, Z_FILTER_FLETCHER32, Z_FILTER_NBIT, Z_FILTER_SCALEOFFSET} {
+		ok, err := FilterAvailable(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("expected built-in filter %v to be available", id)
+		}
+	}
+	if ok, _ := FilterAvailable(32000); ok {
+		t.Error("unexpected available filter 32000")
+	}
+
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err = dcpl.SetChunk(cdims); err != nil {
+		t.Fatal(err)
+	}
+	if err = dcpl.SetShuffle(); err != nil {
+		t.Fatal(err)
+	}
+	if err = dcpl.SetDeflate(BestCompression); err != nil {
+		t.Fatal(err)
+	}
+	if err = dcpl.SetFilter(Z_FILTER_FLETCHER32, Z_FLAG_MANDATORY, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data0, err := save(fn, dsn, dims, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data1, err := load(fn, dsn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compare(data0, data1); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenFile(fn, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dset, err := f.OpenDataset(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	plist, err := dset.CreatePropList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plist.Close()
+
+	want := []FilterInfo{
+		{ID: Z_FILTER_SHUFFLE, Flags: Z_FLAG_OPTIONAL, CDValues: []uint{8}},
+		{ID: Z_FILTER_DEFLATE, Flags: Z_FLAG_OPTIONAL, CDValues: []uint{9}},
+		{ID: Z_FILTER_FLETCHER32, Flags: Z_FLAG_MANDATORY, CDValues: []uint{}},
+	}
+	n, err := plist.NFilters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(want) {
+		t.Fatalf("wrong number of filters: got %d, want %d", n, len(want))
+	}
+	for i, w := range want {
+		got, err := plist.Filter(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != w.ID || got.Flags != w.Flags || fmt.Sprint(got.CDValues) != fmt.Sprint(w.CDValues) {
+			t.Errorf("filter %d: got %v %#x %v, want %v %#x %v", i, got.ID, got.Flags, got.CDValues, w.ID, w.Flags, w.CDValues)
+		}
+		if got.Name == "" || !got.EncodeEnabled || !got.DecodeEnabled {
+			t.Errorf("filter %d: unexpected info %+v", i, got)
+		}
+	}
+	if _, err = plist.Filter(len(want)); err == nil {
+		t.Error("expected an error for a filter index out of range")
+	}
+
+	other, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err = other.SetChunk(cdims); err != nil {
+		t.Fatal(err)
+	}
+	for _, set := range []func() error{
+		other.SetNbit,
+		func() error { return other.SetScaleOffset(Z_SO_FLOAT_DSCALE, 3) },
+		other.SetFletcher32,
+	} {
+		if err := set(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, _ := FilterAvailable(Z_FILTER_SZIP); ok {
+		if err = other.SetSzip(SZIP_NN_OPTION_MASK, 16); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err = other.NFilters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n < 3 {
+		t.Errorf("wrong number of filters: got %d, want at least 3", n)
+	}
+	if info, err := other.Filter(1); err != nil || info.ID != Z_FILTER_SCALEOFFSET {
+		t.Errorf("unexpected second filter: %+v, %v", info, err)
+	}
+}
+
+func TestLayoutAndFill(t *testing.T) {
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fname)
+	defer f.Close()
+	space, err := CreateSimpleDataspace([]uint{10}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	// Contiguous layout filled with NaN when created.
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if status, err := dcpl.FillValueDefined(); err != nil || status != D_FILL_VALUE_DEFAULT {
+		t.Errorf("FillValueDefined: got %v, %v, want %v", status, err, D_FILL_VALUE_DEFAULT)
+	}
+	for _, set := range []error{
+		dcpl.SetLayout(D_CONTIGUOUS),
+		dcpl.SetFillValue(T_NATIVE_DOUBLE, math.NaN()),
+		dcpl.SetAllocTime(D_ALLOC_TIME_EARLY),
+		dcpl.SetFillTime(D_FILL_TIME_ALLOC),
+	} {
+		if set != nil {
+			t.Fatal(set)
+		}
+	}
+	if err := dcpl.SetFillValue(T_NATIVE_DOUBLE, int32(1)); err == nil {
+		t.Error("expected an error for a fill value of the wrong size")
+	}
+	if err := dcpl.SetFillValue(T_GO_STRING, "none"); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType for a fill value holding Go pointers, got %v", err)
+	}
+	if layout, err := dcpl.Layout(); err != nil || layout != D_CONTIGUOUS {
+		t.Errorf("Layout: got %v, %v, want %v", layout, err, D_CONTIGUOUS)
+	}
+	if status, err := dcpl.FillValueDefined(); err != nil || status != D_FILL_VALUE_USER_DEFINED {
+		t.Errorf("FillValueDefined: got %v, %v, want %v", status, err, D_FILL_VALUE_USER_DEFINED)
+	}
+	var fill float64
+	if err := dcpl.FillValue(T_NATIVE_DOUBLE, &fill); err != nil || !math.IsNaN(fill) {
+		t.Errorf("FillValue: got %v, %v, want NaN", fill, err)
+	}
+	if at, err := dcpl.AllocTime(); err != nil || at != D_ALLOC_TIME_EARLY {
+		t.Errorf("AllocTime: got %v, %v, want %v", at, err, D_ALLOC_TIME_EARLY)
+	}
+	if ft, err := dcpl.FillTime(); err != nil || ft != D_FILL_TIME_ALLOC {
+		t.Errorf("FillTime: got %v, %v, want %v", ft, err, D_FILL_TIME_ALLOC)
+	}
+
+	dset, err := f.CreateDatasetWith("contiguous", T_NATIVE_DOUBLE, space, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	if status, err := dset.SpaceStatus(); err != nil || status != D_SPACE_STATUS_ALLOCATED {
+		t.Errorf("SpaceStatus: got %v, %v, want %v", status, err, D_SPACE_STATUS_ALLOCATED)
+	}
+	if size := dset.StorageSize(); size != 10*8 {
+		t.Errorf("StorageSize: got %d, want %d", size, 10*8)
+	}
+	if off, err := dset.Offset(); err != nil || off == 0 {
+		t.Errorf("Offset: got %d, %v", off, err)
+	}
+	data := make([]float64, 10)
+	if err := dset.Read(&data); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range data {
+		if !math.IsNaN(v) {
+			t.Errorf("unexpected value at %d: got %v, want NaN", i, v)
+		}
+	}
+
+	// Compact layout, stored in the object header.
+	compact, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer compact.Close()
+	if err := compact.SetLayout(D_COMPACT); err != nil {
+		t.Fatal(err)
+	}
+	dset, err = f.CreateDatasetWith("compact", T_NATIVE_DOUBLE, space, compact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	plist, err := dset.CreatePropList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plist.Close()
+	if layout, err := plist.Layout(); err != nil || layout != D_COMPACT {
+		t.Errorf("Layout of compact dataset: got %v, %v, want %v", layout, err, D_COMPACT)
+	}
+	if _, err := dset.Offset(); err == nil {
+		t.Error("expected an error for the offset of a compact dataset")
+	}
+
+	// Chunked layout allocated when written.
+	chunked, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chunked.Close()
+	if err := chunked.SetChunk([]uint{5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := chunked.SetAllocTime(D_ALLOC_TIME_INCR); err != nil {
+		t.Fatal(err)
+	}
+	dset, err = f.CreateDatasetWith("chunked", T_NATIVE_DOUBLE, space, chunked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	if status, err := dset.SpaceStatus(); err != nil || status != D_SPACE_STATUS_NOT_ALLOCATED {
+		t.Errorf("SpaceStatus: got %v, %v, want %v", status, err, D_SPACE_STATUS_NOT_ALLOCATED)
+	}
+	if size := dset.StorageSize(); size != 0 {
+		t.Errorf("StorageSize: got %d, want 0", size)
+	}
+}
+
 func save(fn, dsn string, dims []uint, dcpl *PropList) ([]float64, error) {
 	f, err := CreateFile(fn, F_ACC_TRUNC)
 	if err != nil {
@@ -0,0 +1,101 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Plugin types of the loading state mask.
+const (
+	PL_FILTER_PLUGIN uint = C.H5PL_FILTER_PLUGIN // Filter plugins
+	PL_ALL_PLUGIN    uint = C.H5PL_ALL_PLUGIN    // All plugins
+)
+
+// SetPluginLoadingState enables the loading of the plugin types set in mask,
+// such as PL_FILTER_PLUGIN, and disables all the others. By default all the
+// plugin types are enabled, unless the HDF5_PLUGIN_PRELOAD environment
+// variable is set to "::".
+func SetPluginLoadingState(mask uint) error {
+	defer lockThread()()
+	return h5err(C.H5PLset_loading_state(C.uint(mask)))
+}
+
+// PluginLoadingState returns the mask of the plugin types that are enabled.
+func PluginLoadingState() (uint, error) {
+	defer lockThread()()
+	var mask C.uint
+	err := h5err(C.H5PLget_loading_state(&mask))
+	return uint(mask), err
+}
+
+// AppendPluginPath appends dir to the list of directories searched for
+// plugins. The list initially holds the directories of the HDF5_PLUGIN_PATH
+// environment variable, or the default plugin directory of the library.
+func AppendPluginPath(dir string) error {
+	defer lockThread()()
+	c_dir := C.CString(dir)
+	defer C.free(unsafe.Pointer(c_dir))
+	if err := h5err(C.H5PLappend(c_dir)); err != nil {
+		return fmt.Errorf("hdf5: could not append plugin path %q: %w", dir, err)
+	}
+	return nil
+}
+
+// PrependPluginPath prepends dir to the list of directories searched for
+// plugins.
+func PrependPluginPath(dir string) error {
+	defer lockThread()()
+	c_dir := C.CString(dir)
+	defer C.free(unsafe.Pointer(c_dir))
+	if err := h5err(C.H5PLprepend(c_dir)); err != nil {
+		return fmt.Errorf("hdf5: could not prepend plugin path %q: %w", dir, err)
+	}
+	return nil
+}
+
+// NPluginPaths returns the number of directories searched for plugins.
+func NPluginPaths() (int, error) {
+	defer lockThread()()
+	var n C.uint
+	err := h5err(C.H5PLsize(&n))
+	return int(n), err
+}
+
+// PluginPath returns the directory at index i of the list of directories
+// searched for plugins.
+func PluginPath(i int) (string, error) {
+	defer lockThread()()
+	sz := C.H5PLget(C.uint(i), nil, 0)
+	if sz < 0 {
+		return "", fmt.Errorf("hdf5: could not get plugin path %d: %w", i, newErrorStack(int(sz)))
+	}
+	buf := make([]C.char, sz+1)
+	if sz := C.H5PLget(C.uint(i), &buf[0], C.size_t(len(buf))); sz < 0 {
+		return "", fmt.Errorf("hdf5: could not get plugin path %d: %w", i, newErrorStack(int(sz)))
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+// PluginPaths returns the list of directories searched for plugins, in
+// search order.
+func PluginPaths() ([]string, error) {
+	n, err := NPluginPaths()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, n)
+	for i := range paths {
+		if paths[i], err = PluginPath(i); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,112 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestPluginPaths(t *testing.T) {
+	n, err := NPluginPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := PluginPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != n {
+		t.Fatalf("wrong number of plugin paths: got %d, want %d", len(before), n)
+	}
+
+	first, last := t.TempDir(), t.TempDir()
+	if err = AppendPluginPath(last); err != nil {
+		t.Fatal(err)
+	}
+	if err = PrependPluginPath(first); err != nil {
+		t.Fatal(err)
+	}
+	after, err := PluginPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != n+2 || after[0] != first || after[len(after)-1] != last {
+		t.Errorf("unexpected plugin paths %q after adding %q and %q to %q", after, first, last, before)
+	}
+	if _, err = PluginPath(len(after)); err == nil {
+		t.Error("expected an error for a plugin path index out of range")
+	}
+
+	mask, err := PluginLoadingState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetPluginLoadingState(mask)
+	if err = SetPluginLoadingState(0); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := PluginLoadingState(); err != nil || got != 0 {
+		t.Errorf("unexpected loading state %#x: %v", got, err)
+	}
+}
+
+func TestFilterNotAvailable(t *testing.T) {
+	const id = 32101
+	var (
+		fn    = "test_missing_filter.h5"
+		dsn   = "dset_missing_filter"
+		dims  = []uint{100, 100}
+		cdims = []uint{10, 10}
+	)
+	defer os.Remove(fn)
+
+	if err := RegisterFilter(id, "delta", &deltaFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err = dcpl.SetChunk(cdims); err != nil {
+		t.Fatal(err)
+	}
+	if err = dcpl.SetFilter(id, Z_FLAG_MANDATORY, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = save(fn, dsn, dims, dcpl); err != nil {
+		t.Fatal(err)
+	}
+	if err = UnregisterFilter(id); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenFile(fn, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dset, err := f.OpenDataset(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+
+	data := make([]float64, dims[0]*dims[1])
+	err = dset.Read(&data)
+	var missing *FilterNotAvailableError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected a *FilterNotAvailableError, got %v", err)
+	}
+	if missing.ID != id || missing.Name != "delta" {
+		t.Errorf("unexpected missing filter %d (%s)", missing.ID, missing.Name)
+	}
+	var stack *ErrorStack
+	if !errors.As(err, &stack) {
+		t.Errorf("expected the error stack of the read to be wrapped, got %v", err)
+	}
+}
@@ -43,7 +43,13 @@ func (t *Table) IsValid() bool {
 }
 
 // ReadPackets reads a number of packets from a packet table.
+//
+// Packets holding strings or sequences are read through a buffer in C memory
+// and copied into the elements of data. Interface elements are set to values
+// of the Go type of integer, floating-point and variable-length string
+// packets, and to byte arrays holding the memory of other fixed-size packets.
 func (t *Table) ReadPackets(start, nrecords int, data interface{}) error {
+	defer lockThread()()
 	c_start := C.hsize_t(start)
 	c_nrecords := C.size_t(nrecords)
 	rv := reflect.Indirect(reflect.ValueOf(data))
@@ -54,12 +60,22 @@ func (t *Table) ReadPackets(start, nrecords int, data interface{}) error {
 		if rv.Len() < nrecords {
 			panic(fmt.Errorf("not enough capacity in array (cap=%d)", rv.Len()))
 		}
+		if hasGoPointers(rt.Elem()) {
+			return t.readStaged(rv, nrecords, func(buf unsafe.Pointer) C.herr_t {
+				return C.H5PTread_packets(t.id, c_start, c_nrecords, buf)
+			})
+		}
 		c_data = unsafe.Pointer(rv.Index(0).UnsafeAddr())
 
 	case reflect.Slice:
 		if rv.Len() < nrecords {
 			panic(fmt.Errorf("not enough capacity in slice (cap=%d)", rv.Len()))
 		}
+		if hasGoPointers(rt.Elem()) {
+			return t.readStaged(rv, nrecords, func(buf unsafe.Pointer) C.herr_t {
+				return C.H5PTread_packets(t.id, c_start, c_nrecords, buf)
+			})
+		}
 		slice := (*reflect.SliceHeader)(unsafe.Pointer(rv.UnsafeAddr()))
 		c_data = unsafe.Pointer(slice.Data)
 
@@ -73,11 +89,20 @@ func (t *Table) ReadPackets(start, nrecords int, data interface{}) error {
 // Append appends packets to the end of a packet table.
 //
 // Struct values must only have exported fields, otherwise Append will panic.
+// Packets holding strings or slices are staged in C memory, with the layout
+// of the datatype created by NewDataTypeFromType for their Go type.
 func (t *Table) Append(args ...interface{}) error {
+	defer lockThread()()
 	if len(args) == 0 {
 		return fmt.Errorf("hdf5: no arguments passed to packet table append.")
 	}
 
+	for _, arg := range args {
+		if rt := reflect.TypeOf(arg); rt != nil && hasGoPointers(rt) {
+			return t.appendStaged(args)
+		}
+	}
+
 	var enc cmem.Encoder
 	for _, arg := range args {
 		if err := enc.Encode(arg); err != nil {
@@ -92,9 +117,39 @@ func (t *Table) Append(args ...interface{}) error {
 	return h5err(C.H5PTappend(t.id, C.size_t(len(args)), unsafe.Pointer(&enc.Buf[0])))
 }
 
+// appendStaged appends packets holding Go pointers through a buffer in C
+// memory.
+func (t *Table) appendStaged(args []interface{}) error {
+	defer lockThread()()
+	dtype, err := t.Type()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+
+	buf := newCBuffer(uintptr(dtype.Size()), len(args))
+	defer buf.free()
+	for i, arg := range args {
+		layout, err := newMemLayout(reflect.TypeOf(arg))
+		if err != nil {
+			return err
+		}
+		if layout.size != buf.size {
+			return fmt.Errorf("hdf5: packet of type %T has size %d, want %d", arg, layout.size, buf.size)
+		}
+		v := reflect.New(layout.typ)
+		v.Elem().Set(reflect.ValueOf(arg))
+		layout.encode(buf, buf.at(i), v.UnsafePointer())
+	}
+	return h5err(C.H5PTappend(t.id, C.size_t(len(args)), buf.ptr))
+}
+
 // Next reads packets from a packet table starting at the current index into the value pointed at by data.
 // i.e. data is a pointer to an array or a slice.
+//
+// Packets are read into elements holding Go pointers as with ReadPackets.
 func (t *Table) Next(data interface{}) error {
+	defer lockThread()()
 	rt := reflect.TypeOf(data)
 	if rt.Kind() != reflect.Ptr {
 		return fmt.Errorf("hdf5: invalid value type. got=%v, want pointer", rt.Kind())
@@ -123,12 +178,78 @@ func (t *Table) Next(data interface{}) error {
 	default:
 		panic(fmt.Errorf("unsupported kind (%s), need slice or array", rt.Kind()))
 	}
+	if hasGoPointers(rt.Elem()) {
+		return t.readStaged(rv.Slice(0, int(n)), int(n), func(buf unsafe.Pointer) C.herr_t {
+			return C.H5PTget_next(t.id, n, buf)
+		})
+	}
 	err := C.H5PTget_next(t.id, n, cdata)
 	return h5err(err)
 }
 
+// readStaged reads n packets with read through a buffer in C memory into the
+// first elements of rv, a slice or an addressable array whose elements hold
+// Go pointers.
+func (t *Table) readStaged(rv reflect.Value, n int, read func(buf unsafe.Pointer) C.herr_t) error {
+	defer lockThread()()
+	dtype, err := t.Type()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+
+	elem := rv.Type().Elem()
+	typ := elem
+	if elem.Kind() == reflect.Interface {
+		if typ, err = packetGoType(dtype); err != nil {
+			return err
+		}
+	}
+	layout, err := newMemLayout(typ)
+	if err != nil {
+		return err
+	}
+	if layout.size != uintptr(dtype.Size()) {
+		return fmt.Errorf("hdf5: packets of size %d cannot be read into %v", dtype.Size(), typ)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	buf := newCBuffer(layout.size, n)
+	defer buf.free()
+	if err := h5err(read(buf.ptr)); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if elem == typ {
+			layout.decode(rv.Index(i).Addr().UnsafePointer(), buf.at(i))
+			continue
+		}
+		v := reflect.New(typ)
+		layout.decode(v.UnsafePointer(), buf.at(i))
+		rv.Index(i).Set(v.Elem())
+	}
+	return buf.reclaim(dtype, nil)
+}
+
+// packetGoType returns the Go type of the values held in interfaces for
+// packets of datatype dtype.
+func packetGoType(dtype *Datatype) (reflect.Type, error) {
+	switch class := dtype.Class(); {
+	case class == T_INTEGER || class == T_FLOAT:
+		return dtype.GoType(), nil
+	case class == T_STRING && C.H5Tis_variable_str(dtype.id) > 0:
+		return _go_string_t, nil
+	case hasVarLenData(dtype):
+		return nil, fmt.Errorf("hdf5: packets of class %v cannot be read into interface values: %w", class, ErrBadType)
+	}
+	return reflect.ArrayOf(int(dtype.Size()), _go_uint8_t), nil
+}
+
 // NumPackets returns the number of packets in a packet table.
 func (t *Table) NumPackets() (int, error) {
+	defer lockThread()()
 	c_nrecords := C.hsize_t(0)
 	err := C.H5PTget_num_packets(t.id, &c_nrecords)
 	return int(c_nrecords), h5err(err)
@@ -136,12 +257,14 @@ func (t *Table) NumPackets() (int, error) {
 
 // CreateIndex resets a packet table's index to the first packet.
 func (t *Table) CreateIndex() error {
+	defer lockThread()()
 	err := C.H5PTcreate_index(t.id)
 	return h5err(err)
 }
 
 // SetIndex sets a packet table's index.
 func (t *Table) SetIndex(index int) error {
+	defer lockThread()()
 	c_idx := C.hsize_t(index)
 	err := C.H5PTset_index(t.id, c_idx)
 	return h5err(err)
@@ -150,6 +273,7 @@ func (t *Table) SetIndex(index int) error {
 // Type returns an identifier for a copy of the datatype for a dataset. The returned
 // datatype must be closed by the user when it is no longer needed.
 func (t *Table) Type() (*Datatype, error) {
+	defer lockThread()()
 	hid := C.H5Dget_type(t.id)
 	if err := checkID(hid); err != nil {
 		return nil, err
@@ -158,6 +282,7 @@ func (t *Table) Type() (*Datatype, error) {
 }
 
 func createTable(id C.hid_t, name string, dtype *Datatype, chunkSize, compression int) (*Table, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -188,6 +313,7 @@ func createTableFrom(id C.hid_t, name string, dtype interface{}, chunkSize, comp
 }
 
 func openTable(id C.hid_t, name string) (*Table, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
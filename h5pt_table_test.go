@@ -5,6 +5,7 @@
 package hdf5
 
 import (
+	"errors"
 	"os"
 	"reflect"
 	"testing"
@@ -267,3 +268,55 @@ func TestPTableBasic(t *testing.T) {
 		"seven",
 	)
 }
+
+func TestPTStaged(t *testing.T) {
+	type event struct {
+		ID    int32
+		Name  string
+		Steps []float64
+	}
+	events := []event{
+		{ID: 1, Name: "start", Steps: []float64{0}},
+		{ID: 2, Name: "", Steps: []float64{0.5, 1}},
+		{ID: 3, Name: "stop", Steps: []float64{}},
+	}
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fname)
+	defer f.Close()
+
+	table, err := f.CreateTableFrom(tname, event{}, chunkSize, compress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	if err := table.Append(events[0], &events[1], events[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Append("wrong"); err == nil {
+		t.Error("expected an error appending a string to a table of structs")
+	}
+
+	got := make([]event, len(events))
+	if err := table.ReadPackets(0, len(events), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("wrong packets:\ngot= %+v\nwant=%+v", got, events)
+	}
+
+	var next [2]event
+	if err := table.Next(&next); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(next[:], events[:2]) {
+		t.Errorf("wrong next packets:\ngot= %+v\nwant=%+v", next, events[:2])
+	}
+	p := make([]interface{}, 1)
+	if err := table.Next(&p); !errors.Is(err, ErrBadType) {
+		t.Errorf("expected ErrBadType reading a compound into an interface, got %v", err)
+	}
+}
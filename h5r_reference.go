@@ -0,0 +1,147 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Reference is an object reference, the in-memory value of the
+// T_STD_REF_OBJ datatype. A []Reference can be written to and read from
+// a dataset created with T_STD_REF_OBJ.
+type Reference C.hobj_ref_t
+
+// RegionReference is a dataset region reference, the in-memory value of
+// the T_STD_REF_DSETREG datatype. It points to a selection of elements in
+// a dataset. A []RegionReference can be written to and read from a dataset
+// created with T_STD_REF_DSETREG.
+type RegionReference C.hdset_reg_ref_t
+
+var (
+	_go_reference_t        reflect.Type = reflect.TypeOf(Reference(0))
+	_go_region_reference_t reflect.Type = reflect.TypeOf(RegionReference{})
+)
+
+// CreateReference returns a reference to the object at path, relative to
+// this location.
+func (g *CommonFG) CreateReference(path string) (Reference, error) {
+	defer lockThread()()
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	var ref Reference
+	err := h5err(C.H5Rcreate(unsafe.Pointer(&ref), g.id, c_path, C.H5R_OBJECT, -1))
+	if err != nil {
+		return 0, fmt.Errorf("hdf5: could not create reference to %q: %w", path, err)
+	}
+	return ref, nil
+}
+
+// CreateRegionReference returns a reference to the elements selected in
+// space of dset. The space must have the extent of the dataset.
+func CreateRegionReference(dset *Dataset, space *Dataspace) (RegionReference, error) {
+	defer lockThread()()
+	c_name := C.CString(".")
+	defer C.free(unsafe.Pointer(c_name))
+
+	var ref RegionReference
+	err := h5err(C.H5Rcreate(unsafe.Pointer(&ref), dset.id, c_name, C.H5R_DATASET_REGION, space.id))
+	if err != nil {
+		return ref, fmt.Errorf("hdf5: could not create region reference to %q: %w", dset.Name(), err)
+	}
+	return ref, nil
+}
+
+// ObjectType returns the type of the object ref points to.
+func (f *File) ObjectType(ref Reference) (ObjectType, error) {
+	defer lockThread()()
+	var typ C.H5O_type_t
+	err := h5err(C.H5Rget_obj_type2(f.id, C.H5R_OBJECT, unsafe.Pointer(&ref), &typ))
+	if err != nil {
+		return O_TYPE_UNKNOWN, err
+	}
+	return ObjectType(typ), nil
+}
+
+// Dereference opens the object ref points to. The returned object is a
+// *Group, *Dataset or *Datatype and must be closed by the user when it is
+// no longer needed.
+func (f *File) Dereference(ref Reference) (Object, error) {
+	defer lockThread()()
+	hid := C.H5Rdereference2(f.id, P_DEFAULT.id, C.H5R_OBJECT, unsafe.Pointer(&ref))
+	if err := checkID(hid); err != nil {
+		return nil, fmt.Errorf("hdf5: could not dereference object reference: %w", err)
+	}
+	return newObject(hid)
+}
+
+// newObject wraps an open object identifier into its Go type.
+func newObject(hid C.hid_t) (Object, error) {
+	switch typ := IType(C.H5Iget_type(hid)); typ {
+	case GROUP:
+		return &Group{CommonFG{Location{Identifier{hid}}}}, nil
+	case DATASET:
+		return newDataset(hid, nil), nil
+	case DATATYPE:
+		return NewDatatype(hid), nil
+	default:
+		C.H5Oclose(hid)
+		return nil, fmt.Errorf("hdf5: unexpected object type %v", typ)
+	}
+}
+
+// DereferenceRegion opens the dataset ref points to and returns it with a
+// copy of its dataspace on which the referenced selection is applied. Both
+// must be closed by the user when they are no longer needed.
+func (f *File) DereferenceRegion(ref RegionReference) (*Dataset, *Dataspace, error) {
+	defer lockThread()()
+	c_ref := unsafe.Pointer(&ref)
+	hid := C.H5Rdereference2(f.id, P_DEFAULT.id, C.H5R_DATASET_REGION, c_ref)
+	if err := checkID(hid); err != nil {
+		return nil, nil, fmt.Errorf("hdf5: could not dereference region reference: %w", err)
+	}
+	dset := newDataset(hid, nil)
+
+	sid := C.H5Rget_region(hid, C.H5R_DATASET_REGION, c_ref)
+	if err := checkID(sid); err != nil {
+		dset.Close()
+		return nil, nil, fmt.Errorf("hdf5: could not get region of reference: %w", err)
+	}
+	return dset, newDataspace(sid), nil
+}
+
+// ReadRegion reads the elements referenced by ref into data, which must
+// be a pointer to a slice or array large enough to hold all the selected
+// elements. The elements are stored in the order of the selection.
+func (f *File) ReadRegion(ref RegionReference, data interface{}) error {
+	dset, filespace, err := f.DereferenceRegion(ref)
+	if err != nil {
+		return err
+	}
+	defer dset.Close()
+	defer filespace.Close()
+
+	n := C.H5Sget_select_npoints(filespace.id)
+	if n < 0 {
+		return fmt.Errorf("hdf5: could not get number of selected elements of %q", dset.Name())
+	}
+	if v := reflect.Indirect(reflect.ValueOf(data)); (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Len() < int(n) {
+		return fmt.Errorf("hdf5: buffer too small for region of %q: got %d, need %d elements", dset.Name(), v.Len(), int(n))
+	}
+
+	memspace, err := CreateSimpleDataspace([]uint{uint(n)}, nil)
+	if err != nil {
+		return err
+	}
+	defer memspace.Close()
+	return dset.ReadSubset(data, memspace, filespace)
+}
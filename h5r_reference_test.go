@@ -0,0 +1,202 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"os"
+	"testing"
+)
+
+func TestObjectReference(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+	defer os.Remove(fname)
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer f.Close()
+
+	g, err := f.CreateGroup("results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scalar.Close()
+
+	flow, err := g.CreateDataset("flow", T_NATIVE_DOUBLE, scalar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer flow.Close()
+
+	refs := make([]Reference, 2)
+	for i, path := range []string{"/results", "/results/flow"} {
+		refs[i], err = f.CreateReference(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = f.CreateReference("/missing"); err == nil {
+		t.Error("expected an error creating a reference to a missing object")
+	}
+
+	dtype, err := NewDatatypeFromValue(refs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	if !dtype.Equal(T_STD_REF_OBJ) {
+		t.Fatal("Reference does not map to T_STD_REF_OBJ")
+	}
+
+	space, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	index, err := f.CreateDataset("index", T_STD_REF_OBJ, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	if err = index.Write(&refs); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]Reference, 2)
+	if err = index.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []struct {
+		name string
+		typ  ObjectType
+	}{
+		{"/results", O_TYPE_GROUP},
+		{"/results/flow", O_TYPE_DATASET},
+	} {
+		typ, err := f.ObjectType(got[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != want.typ {
+			t.Errorf("wrong object type for reference %d: got %v, want %v", i, typ, want.typ)
+		}
+
+		obj, err := f.Dereference(got[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if obj.Name() != want.name {
+			t.Errorf("wrong object for reference %d: got %q, want %q", i, obj.Name(), want.name)
+		}
+		if err = obj.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestRegionReference(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+	defer os.Remove(fname)
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer f.Close()
+
+	space, err := CreateSimpleDataspace([]uint{300}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+
+	flow, err := f.CreateDataset("flow", T_NATIVE_DOUBLE, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer flow.Close()
+
+	data := make([]float64, 300)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	if err = flow.Write(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = space.SelectHyperslab([]uint{100}, nil, []uint{100}, nil); err != nil {
+		t.Fatal(err)
+	}
+	ref, err := CreateRegionReference(flow, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refspace, err := CreateSimpleDataspace([]uint{1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer refspace.Close()
+
+	rtype, err := NewDatatypeFromValue(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rtype.Close()
+	if !rtype.Equal(T_STD_REF_DSETREG) {
+		t.Fatal("RegionReference does not map to T_STD_REF_DSETREG")
+	}
+
+	index, err := f.CreateDataset("index", T_STD_REF_DSETREG, refspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	refs := []RegionReference{ref}
+	if err = index.Write(&refs); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]RegionReference, 1)
+	if err = index.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	dset, sel, err := f.DereferenceRegion(got[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dset.Name() != "/flow" {
+		t.Errorf("wrong dataset for region reference: got %q, want %q", dset.Name(), "/flow")
+	}
+	sel.Close()
+	dset.Close()
+
+	rows := make([]float64, 100)
+	if err = f.ReadRegion(got[0], &rows); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range rows {
+		if want := float64(100 + i); v != want {
+			t.Fatalf("wrong value at %d: got %v, want %v", i, v, want)
+		}
+	}
+
+	short := make([]float64, 10)
+	if err = f.ReadRegion(got[0], &short); err == nil {
+		t.Error("expected an error reading a region into a short buffer")
+	}
+}
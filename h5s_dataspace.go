@@ -29,6 +29,55 @@ const (
 	S_NULL     SpaceClass = 2  // null data space
 )
 
+// SelectOp is the operation combining a new selection with the current
+// selection of a dataspace.
+type SelectOp C.H5S_seloper_t
+
+const (
+	S_SELECT_SET     SelectOp = C.H5S_SELECT_SET     // replace the selection
+	S_SELECT_OR      SelectOp = C.H5S_SELECT_OR      // union of the selections
+	S_SELECT_AND     SelectOp = C.H5S_SELECT_AND     // intersection of the selections
+	S_SELECT_XOR     SelectOp = C.H5S_SELECT_XOR     // elements in exactly one of the selections
+	S_SELECT_NOTB    SelectOp = C.H5S_SELECT_NOTB    // elements of the current selection not in the new one
+	S_SELECT_NOTA    SelectOp = C.H5S_SELECT_NOTA    // elements of the new selection not in the current one
+	S_SELECT_APPEND  SelectOp = C.H5S_SELECT_APPEND  // points added after the current point selection
+	S_SELECT_PREPEND SelectOp = C.H5S_SELECT_PREPEND // points added before the current point selection
+)
+
+func (op SelectOp) String() string {
+	switch op {
+	case S_SELECT_SET:
+		return "set"
+	case S_SELECT_OR:
+		return "or"
+	case S_SELECT_AND:
+		return "and"
+	case S_SELECT_XOR:
+		return "xor"
+	case S_SELECT_NOTB:
+		return "notb"
+	case S_SELECT_NOTA:
+		return "nota"
+	case S_SELECT_APPEND:
+		return "append"
+	case S_SELECT_PREPEND:
+		return "prepend"
+	default:
+		return fmt.Sprintf("SelectOp(%d)", int(op))
+	}
+}
+
+// HyperslabBlock is a block of a hyperslab selection, from the coordinates
+// of its first element to those of its last element, inclusive.
+type HyperslabBlock struct {
+	Start, End []uint
+}
+
+// S_UNLIMITED is the value of H5S_UNLIMITED. It can be used in the maxDims
+// argument of CreateSimpleDataspace to declare a dimension that can be
+// extended without limit. Datasets with unlimited dimensions must be chunked.
+const S_UNLIMITED uint = C.H5S_UNLIMITED
+
 func newDataspace(id C.hid_t) *Dataspace {
 	return &Dataspace{Identifier{id}}
 }
@@ -36,6 +85,7 @@ func newDataspace(id C.hid_t) *Dataspace {
 // CreateDataspace creates a new dataspace of a specified type. The returned
 // dataspace must be closed by the user when it is no longer needed.
 func CreateDataspace(class SpaceClass) (*Dataspace, error) {
+	defer lockThread()()
 	hid := C.H5Screate(C.H5S_class_t(class))
 	if err := checkID(hid); err != nil {
 		return nil, err
@@ -47,6 +97,7 @@ func CreateDataspace(class SpaceClass) (*Dataspace, error) {
 // Copy creates an exact copy of a dataspace. The returned dataspace must
 // be closed by the user when it is no longer needed.
 func (s *Dataspace) Copy() (*Dataspace, error) {
+	defer lockThread()()
 	hid := C.H5Scopy(s.id)
 	if err := checkID(hid); err != nil {
 		return nil, err
@@ -97,6 +148,7 @@ func (s *Dataspace) IsSimple() bool {
 
 // SetOffset sets the offset of a simple dataspace.
 func (s *Dataspace) SetOffset(offset []uint) error {
+	defer lockThread()()
 	rank := len(offset)
 	if rank == 0 {
 		err := C.H5Soffset_simple(s.id, nil)
@@ -114,6 +166,15 @@ func (s *Dataspace) SetOffset(offset []uint) error {
 
 // SelectHyperslab creates a subset of the data space.
 func (s *Dataspace) SelectHyperslab(offset, stride, count, block []uint) error {
+	return s.SelectHyperslabOp(S_SELECT_SET, offset, stride, count, block)
+}
+
+// SelectHyperslabOp combines the hyperslab given by offset, stride, count
+// and block with the current selection of the dataspace, according to op.
+// The operations S_SELECT_APPEND and S_SELECT_PREPEND only apply to point
+// selections, see SelectElementsOp.
+func (s *Dataspace) SelectHyperslabOp(op SelectOp, offset, stride, count, block []uint) error {
+	defer lockThread()()
 	rank := len(offset)
 	if rank == 0 {
 		err := C.H5Soffset_simple(s.id, nil)
@@ -133,12 +194,163 @@ func (s *Dataspace) SelectHyperslab(offset, stride, count, block []uint) error {
 	if block != nil {
 		c_block = (*C.hsize_t)(unsafe.Pointer(&block[0]))
 	}
-	err := C.H5Sselect_hyperslab(s.id, C.H5S_SELECT_SET, c_offset, c_stride, c_count, c_block)
+	err := C.H5Sselect_hyperslab(s.id, C.H5S_seloper_t(op), c_offset, c_stride, c_count, c_block)
 	return h5err(err)
 }
 
+// SelectElements selects the points of the dataspace at the coordinates
+// points, in that order. Each point has the rank of the dataspace. Reading
+// or writing with the selection goes through the points in the order given,
+// so scattered elements are transferred in a single call.
+func (s *Dataspace) SelectElements(points [][]uint) error {
+	return s.SelectElementsOp(S_SELECT_SET, points)
+}
+
+// SelectElementsOp combines the points at the coordinates points with the
+// current selection of the dataspace. The op is S_SELECT_SET,
+// S_SELECT_APPEND or S_SELECT_PREPEND.
+func (s *Dataspace) SelectElementsOp(op SelectOp, points [][]uint) error {
+	defer lockThread()()
+	rank := s.SimpleExtentNDims()
+	if rank <= 0 {
+		return errors.New("dataspace is not simple")
+	}
+	if len(points) == 0 {
+		return errors.New("no points to select")
+	}
+	c_coord := make([]C.hsize_t, 0, len(points)*rank)
+	for i, p := range points {
+		if len(p) != rank {
+			return fmt.Errorf("size of point %d (%d) does not match rank of dataspace (%d)", i, len(p), rank)
+		}
+		for _, x := range p {
+			c_coord = append(c_coord, C.hsize_t(x))
+		}
+	}
+	return h5err(C.H5Sselect_elements(s.id, C.H5S_seloper_t(op), C.size_t(len(points)), &c_coord[0]))
+}
+
+// SelectAll selects the whole extent of the dataspace.
+func (s *Dataspace) SelectAll() error {
+	defer lockThread()()
+	return h5err(C.H5Sselect_all(s.id))
+}
+
+// SelectNone resets the selection of the dataspace to no elements.
+func (s *Dataspace) SelectNone() error {
+	defer lockThread()()
+	return h5err(C.H5Sselect_none(s.id))
+}
+
+// SelectValid returns whether the selection of the dataspace, moved by the
+// offset set with SetOffset, is within its extent.
+func (s *Dataspace) SelectValid() (bool, error) {
+	defer lockThread()()
+	rc := C.H5Sselect_valid(s.id)
+	if err := h5err(C.herr_t(rc)); err != nil {
+		return false, err
+	}
+	return rc > 0, nil
+}
+
+// SelectionNPoints returns the number of elements selected in the dataspace.
+func (s *Dataspace) SelectionNPoints() (int, error) {
+	defer lockThread()()
+	n := C.H5Sget_select_npoints(s.id)
+	if n < 0 {
+		return 0, newErrorStack(int(n))
+	}
+	return int(n), nil
+}
+
+// SelectionBounds returns the coordinates of the opposite corners of the
+// bounding box of the selection of the dataspace, inclusive.
+func (s *Dataspace) SelectionBounds() (start, end []uint, err error) {
+	defer lockThread()()
+	rank := s.SimpleExtentNDims()
+	if rank <= 0 {
+		return nil, nil, errors.New("dataspace is not simple")
+	}
+	c_start := make([]C.hsize_t, rank)
+	c_end := make([]C.hsize_t, rank)
+	if err := h5err(C.H5Sget_select_bounds(s.id, &c_start[0], &c_end[0])); err != nil {
+		return nil, nil, err
+	}
+	start = make([]uint, rank)
+	end = make([]uint, rank)
+	for i := range start {
+		start[i] = uint(c_start[i])
+		end[i] = uint(c_end[i])
+	}
+	return start, end, nil
+}
+
+// HyperslabBlocks returns the blocks of the hyperslab selection of the
+// dataspace. It returns an error for other kinds of selections.
+func (s *Dataspace) HyperslabBlocks() ([]HyperslabBlock, error) {
+	defer lockThread()()
+	rank := s.SimpleExtentNDims()
+	if rank <= 0 {
+		return nil, errors.New("dataspace is not simple")
+	}
+	n := C.H5Sget_select_hyper_nblocks(s.id)
+	if n < 0 {
+		return nil, newErrorStack(int(n))
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	buf := make([]C.hsize_t, int(n)*2*rank)
+	if err := h5err(C.H5Sget_select_hyper_blocklist(s.id, 0, C.hsize_t(n), &buf[0])); err != nil {
+		return nil, err
+	}
+	blocks := make([]HyperslabBlock, n)
+	for i := range blocks {
+		coords := buf[i*2*rank:]
+		b := HyperslabBlock{Start: make([]uint, rank), End: make([]uint, rank)}
+		for j := 0; j < rank; j++ {
+			b.Start[j] = uint(coords[j])
+			b.End[j] = uint(coords[rank+j])
+		}
+		blocks[i] = b
+	}
+	return blocks, nil
+}
+
+// ElementPoints returns the coordinates of the points of the point
+// selection of the dataspace, in selection order. It returns an error for
+// other kinds of selections.
+func (s *Dataspace) ElementPoints() ([][]uint, error) {
+	defer lockThread()()
+	rank := s.SimpleExtentNDims()
+	if rank <= 0 {
+		return nil, errors.New("dataspace is not simple")
+	}
+	n := C.H5Sget_select_elem_npoints(s.id)
+	if n < 0 {
+		return nil, newErrorStack(int(n))
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	buf := make([]C.hsize_t, int(n)*rank)
+	if err := h5err(C.H5Sget_select_elem_pointlist(s.id, 0, C.hsize_t(n), &buf[0])); err != nil {
+		return nil, err
+	}
+	points := make([][]uint, n)
+	for i := range points {
+		p := make([]uint, rank)
+		for j := range p {
+			p[j] = uint(buf[i*rank+j])
+		}
+		points[i] = p
+	}
+	return points, nil
+}
+
 // SimpleExtentDims returns dataspace dimension size and maximum size.
 func (s *Dataspace) SimpleExtentDims() (dims, maxdims []uint, err error) {
+	defer lockThread()()
 	rank := s.SimpleExtentNDims()
 	dims = make([]uint, rank)
 	maxdims = make([]uint, rank)
@@ -5,6 +5,8 @@
 package hdf5
 
 import (
+	"os"
+	"reflect"
 	"testing"
 )
 
@@ -103,3 +105,161 @@ func arrayEq(a, b []uint) bool {
 	}
 	return true
 }
+
+func TestSelections(t *testing.T) {
+	ds, err := CreateSimpleDataspace([]uint{10, 10}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+
+	npoints := func(want int) {
+		t.Helper()
+		if n, err := ds.SelectionNPoints(); err != nil || n != want {
+			t.Errorf("SelectionNPoints: got %d, %v, want %d", n, err, want)
+		}
+	}
+	npoints(100)
+	if err := ds.SelectNone(); err != nil {
+		t.Fatal(err)
+	}
+	npoints(0)
+
+	// Hyperslab set operations.
+	if err := ds.SelectHyperslab([]uint{0, 0}, nil, []uint{1, 1}, []uint{2, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SelectHyperslabOp(S_SELECT_OR, []uint{5, 5}, nil, []uint{1, 1}, []uint{3, 3}); err != nil {
+		t.Fatal(err)
+	}
+	npoints(4 + 9)
+	blocks, err := ds.HyperslabBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HyperslabBlock{
+		{Start: []uint{0, 0}, End: []uint{1, 1}},
+		{Start: []uint{5, 5}, End: []uint{7, 7}},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("HyperslabBlocks: got %v, want %v", blocks, want)
+	}
+	if start, end, err := ds.SelectionBounds(); err != nil || !reflect.DeepEqual(start, []uint{0, 0}) || !reflect.DeepEqual(end, []uint{7, 7}) {
+		t.Errorf("SelectionBounds: got %v, %v, %v, want [0 0], [7 7]", start, end, err)
+	}
+	if err := ds.SelectHyperslabOp(S_SELECT_AND, []uint{1, 1}, nil, []uint{1, 1}, []uint{5, 5}); err != nil {
+		t.Fatal(err)
+	}
+	npoints(1 + 1)
+	if err := ds.SelectHyperslabOp(S_SELECT_XOR, []uint{0, 0}, nil, []uint{1, 1}, []uint{2, 2}); err != nil {
+		t.Fatal(err)
+	}
+	npoints(3 + 1)
+	if err := ds.SelectHyperslabOp(S_SELECT_NOTB, []uint{5, 5}, nil, []uint{1, 1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	npoints(3)
+	if err := ds.SelectHyperslabOp(S_SELECT_NOTA, []uint{0, 0}, nil, []uint{1, 1}, []uint{3, 3}); err != nil {
+		t.Fatal(err)
+	}
+	npoints(9 - 3)
+	if _, err := ds.ElementPoints(); err == nil {
+		t.Error("expected an error for the points of a hyperslab selection")
+	}
+
+	// Point selections, kept in order.
+	points := [][]uint{{3, 4}, {0, 9}, {7, 1}}
+	if err := ds.SelectElements(points); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SelectElementsOp(S_SELECT_APPEND, [][]uint{{2, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SelectElementsOp(S_SELECT_PREPEND, [][]uint{{9, 9}}); err != nil {
+		t.Fatal(err)
+	}
+	npoints(5)
+	got, err := ds.ElementPoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]uint{{9, 9}, {3, 4}, {0, 9}, {7, 1}, {2, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ElementPoints: got %v, want %v", got, want)
+	}
+	if start, end, err := ds.SelectionBounds(); err != nil || !reflect.DeepEqual(start, []uint{0, 1}) || !reflect.DeepEqual(end, []uint{9, 9}) {
+		t.Errorf("SelectionBounds: got %v, %v, %v, want [0 1], [9 9]", start, end, err)
+	}
+	if _, err := ds.HyperslabBlocks(); err == nil {
+		t.Error("expected an error for the blocks of a point selection")
+	}
+	if err := ds.SelectElements([][]uint{{1, 2, 3}}); err == nil {
+		t.Error("expected an error for a point of the wrong rank")
+	}
+
+	if ok, err := ds.SelectValid(); err != nil || !ok {
+		t.Errorf("SelectValid: got %t, %v, want true", ok, err)
+	}
+	if err := ds.SetOffset([]uint{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ds.SelectValid(); err != nil || ok {
+		t.Errorf("SelectValid with offset: got %t, %v, want false", ok, err)
+	}
+	if err := ds.SetOffset([]uint{0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SelectAll(); err != nil {
+		t.Fatal(err)
+	}
+	npoints(100)
+}
+
+func TestReadElements(t *testing.T) {
+	defer os.Remove(fname)
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dims := []uint{20, 30}
+	space, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dset, err := f.CreateDataset("cells", T_NATIVE_INT32, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	data := make([]int32, dims[0]*dims[1])
+	for i := range data {
+		data[i] = int32(i)
+	}
+	if err := dset.Write(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	points := [][]uint{{19, 29}, {0, 0}, {7, 3}, {12, 25}}
+	filespace := dset.Space()
+	defer filespace.Close()
+	if err := filespace.SelectElements(points); err != nil {
+		t.Fatal(err)
+	}
+	memspace, err := CreateSimpleDataspace([]uint{uint(len(points))}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memspace.Close()
+
+	got := make([]int32, len(points))
+	if err := dset.ReadSubset(&got, memspace, filespace); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range points {
+		if want := int32(p[0]*dims[1] + p[1]); got[i] != want {
+			t.Errorf("wrong value for point %v: got %d, want %d", p, got[i], want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+//
+// // H5Dvlen_reclaim is deprecated in favour of H5Treclaim from HDF5 1.12.
+// static inline herr_t _go_hdf5_reclaim(hid_t type_id, hid_t space_id, void *buf) {
+// #if H5_VERSION_GE(1, 12, 0)
+//   return H5Treclaim(type_id, space_id, H5P_DEFAULT, buf);
+// #else
+//   return H5Dvlen_reclaim(type_id, space_id, H5P_DEFAULT, buf);
+// #endif
+// }
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// The cgo rules forbid passing the library memory holding Go pointers, such
+// as the contents of Go strings and slices. Values holding them are staged
+// in C memory instead: strings are stored as C strings, slices as hvl_t
+// sequences and structs with the member offsets of the equivalent C struct.
+// The datatype created by NewDataTypeFromType describes this layout.
+
+// memLayout describes how the values of a Go type are stored in C memory.
+type memLayout struct {
+	typ   reflect.Type
+	size  uintptr
+	align uintptr
+
+	// fixed reports whether the values hold no Go pointers, in which case
+	// they are stored as they are in Go memory.
+	fixed bool
+
+	elem    *memLayout   // elements of arrays and slices, pointees of pointers
+	fields  []*memLayout // fields of structs
+	offsets []uintptr    // offsets of the fields of structs
+}
+
+var (
+	cPtrSize  = unsafe.Sizeof(uintptr(0))
+	cPtrAlign = unsafe.Alignof(uintptr(0))
+	cVlenSize = unsafe.Sizeof(C.hvl_t{})
+)
+
+// newMemLayout returns the layout in C memory of the Go type t.
+func newMemLayout(t reflect.Type) (*memLayout, error) {
+	if isFixedSize(t) {
+		return &memLayout{typ: t, size: t.Size(), align: uintptr(t.Align()), fixed: true}, nil
+	}
+	l := &memLayout{typ: t}
+	switch t.Kind() {
+	case reflect.String:
+		l.size, l.align = cPtrSize, cPtrAlign
+
+	case reflect.Slice:
+		elem, err := newMemLayout(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		l.elem = elem
+		l.size, l.align = cVlenSize, cPtrAlign
+
+	case reflect.Array:
+		elem, err := newMemLayout(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		l.elem = elem
+		l.size, l.align = uintptr(t.Len())*elem.size, elem.align
+
+	case reflect.Ptr:
+		elem, err := newMemLayout(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		l.elem = elem
+		l.size, l.align = elem.size, elem.align
+
+	case reflect.Struct:
+		l.align = 1
+		for i := 0; i < t.NumField(); i++ {
+			f, err := newMemLayout(t.Field(i).Type)
+			if err != nil {
+				return nil, err
+			}
+			offset := alignUp(l.size, f.align)
+			l.fields = append(l.fields, f)
+			l.offsets = append(l.offsets, offset)
+			l.size = offset + f.size
+			l.align = max(l.align, f.align)
+		}
+		l.size = alignUp(l.size, l.align)
+
+	default:
+		return nil, fmt.Errorf("hdf5: unsupported Go type %v: %w", t, ErrBadType)
+	}
+	return l, nil
+}
+
+func alignUp(n, align uintptr) uintptr {
+	return (n + align - 1) &^ (align - 1)
+}
+
+// offset returns the offset in C memory of the i-th field of a struct.
+func (l *memLayout) offset(i int) uintptr {
+	if l.fixed {
+		return l.typ.Field(i).Offset
+	}
+	return l.offsets[i]
+}
+
+// encode stores the Go value at src into the C memory at dst, allocating
+// the C strings and sequences it refers to in b.
+func (l *memLayout) encode(b *cBuffer, dst, src unsafe.Pointer) {
+	if l.fixed {
+		copy(unsafe.Slice((*byte)(dst), l.size), unsafe.Slice((*byte)(src), l.size))
+		return
+	}
+	switch l.typ.Kind() {
+	case reflect.String:
+		*(**C.char)(dst) = b.cstring(*(*string)(src))
+
+	case reflect.Slice:
+		v := reflect.NewAt(l.typ, src).Elem()
+		vl := (*C.hvl_t)(dst)
+		vl.len = C.size_t(v.Len())
+		vl.p = nil
+		if v.Len() == 0 {
+			return
+		}
+		vl.p = b.alloc(uintptr(v.Len()) * l.elem.size)
+		l.elem.encodeN(b, vl.p, v.Index(0).Addr().UnsafePointer(), v.Len())
+
+	case reflect.Array:
+		l.elem.encodeN(b, dst, src, l.typ.Len())
+
+	case reflect.Ptr:
+		if p := *(*unsafe.Pointer)(src); p != nil {
+			l.elem.encode(b, dst, p)
+		}
+
+	case reflect.Struct:
+		for i, f := range l.fields {
+			f.encode(b, unsafe.Add(dst, l.offsets[i]), unsafe.Add(src, l.typ.Field(i).Offset))
+		}
+	}
+}
+
+// encodeN stores the n consecutive Go values at src into the C memory at dst.
+func (l *memLayout) encodeN(b *cBuffer, dst, src unsafe.Pointer, n int) {
+	if l.fixed {
+		copy(unsafe.Slice((*byte)(dst), uintptr(n)*l.size), unsafe.Slice((*byte)(src), uintptr(n)*l.size))
+		return
+	}
+	for i := 0; i < n; i++ {
+		l.encode(b, unsafe.Add(dst, uintptr(i)*l.size), unsafe.Add(src, uintptr(i)*l.typ.Size()))
+	}
+}
+
+// decode stores the value in the C memory at src into the Go value at dst.
+// The strings and sequences it refers to are copied into Go memory.
+func (l *memLayout) decode(dst, src unsafe.Pointer) {
+	if l.fixed {
+		copy(unsafe.Slice((*byte)(dst), l.size), unsafe.Slice((*byte)(src), l.size))
+		return
+	}
+	switch l.typ.Kind() {
+	case reflect.String:
+		*(*string)(dst) = C.GoString(*(**C.char)(src))
+
+	case reflect.Slice:
+		vl := (*C.hvl_t)(src)
+		n := int(vl.len)
+		v := reflect.MakeSlice(l.typ, n, n)
+		if n > 0 {
+			l.elem.decodeN(v.Index(0).Addr().UnsafePointer(), vl.p, n)
+		}
+		reflect.NewAt(l.typ, dst).Elem().Set(v)
+
+	case reflect.Array:
+		l.elem.decodeN(dst, src, l.typ.Len())
+
+	case reflect.Ptr:
+		v := reflect.New(l.typ.Elem())
+		l.elem.decode(v.UnsafePointer(), src)
+		reflect.NewAt(l.typ, dst).Elem().Set(v)
+
+	case reflect.Struct:
+		for i, f := range l.fields {
+			f.decode(unsafe.Add(dst, l.typ.Field(i).Offset), unsafe.Add(src, l.offsets[i]))
+		}
+	}
+}
+
+// decodeN stores the n consecutive values in the C memory at src into the
+// Go values at dst.
+func (l *memLayout) decodeN(dst, src unsafe.Pointer, n int) {
+	if l.fixed {
+		copy(unsafe.Slice((*byte)(dst), uintptr(n)*l.size), unsafe.Slice((*byte)(src), uintptr(n)*l.size))
+		return
+	}
+	for i := 0; i < n; i++ {
+		l.decode(unsafe.Add(dst, uintptr(i)*l.typ.Size()), unsafe.Add(src, uintptr(i)*l.size))
+	}
+}
+
+// cBuffer is a buffer of n elements of the given size in C memory. It keeps
+// track of the C memory allocated for the strings and sequences staged in
+// it, which is released by free.
+type cBuffer struct {
+	ptr  unsafe.Pointer
+	size uintptr
+	n    int
+
+	allocs []unsafe.Pointer
+}
+
+func newCBuffer(size uintptr, n int) *cBuffer {
+	b := &cBuffer{size: size, n: n}
+	b.ptr = b.alloc(size * uintptr(n))
+	return b
+}
+
+// alloc returns size bytes of zeroed C memory.
+func (b *cBuffer) alloc(size uintptr) unsafe.Pointer {
+	if size == 0 {
+		return nil
+	}
+	p := C.calloc(1, C.size_t(size))
+	if p == nil {
+		panic("hdf5: out of C memory")
+	}
+	b.allocs = append(b.allocs, p)
+	return p
+}
+
+// cstring returns a copy of s in C memory.
+func (b *cBuffer) cstring(s string) *C.char {
+	p := C.CString(s)
+	b.allocs = append(b.allocs, unsafe.Pointer(p))
+	return p
+}
+
+// at returns the address of the i-th element of the buffer.
+func (b *cBuffer) at(i int) unsafe.Pointer {
+	return unsafe.Add(b.ptr, uintptr(i)*b.size)
+}
+
+// encode stages the n Go values of layout l at src in the buffer.
+func (b *cBuffer) encode(l *memLayout, src unsafe.Pointer) {
+	l.encodeN(b, b.ptr, src, b.n)
+}
+
+// decode copies the elements of the buffer into the n Go values of layout
+// l at dst.
+func (b *cBuffer) decode(l *memLayout, dst unsafe.Pointer) {
+	l.decodeN(dst, b.ptr, b.n)
+}
+
+// reclaim releases the memory allocated by the library for the strings and
+// sequences of the elements of the buffer of datatype dtype selected in
+// space, or of all of them if space is nil.
+func (b *cBuffer) reclaim(dtype *Datatype, space *Dataspace) error {
+	defer lockThread()()
+	if b.n == 0 {
+		return nil
+	}
+	if space == nil {
+		var err error
+		space, err = CreateSimpleDataspace([]uint{uint(b.n)}, nil)
+		if err != nil {
+			return err
+		}
+		defer space.Close()
+	}
+	return h5err(C._go_hdf5_reclaim(dtype.id, space.id, b.ptr))
+}
+
+// free releases the C memory of the buffer.
+func (b *cBuffer) free() {
+	for _, p := range b.allocs {
+		C.free(p)
+	}
+	b.allocs = nil
+	b.ptr = nil
+}
+
+// hasGoPointers returns whether the values of type t hold Go pointers, so
+// that they must be staged in C memory to be passed to the library.
+func hasGoPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Ptr, reflect.Interface,
+		reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return hasGoPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasGoPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bufferElems returns the address and the number of the elements of type t
+// of the buffer v, a slice, an array or a single value. v must be
+// addressable unless it is a slice.
+func bufferElems(v reflect.Value, t reflect.Type) (unsafe.Pointer, int) {
+	if v.Kind() == reflect.Slice {
+		if v.Len() == 0 {
+			return nil, 0
+		}
+		return v.Index(0).Addr().UnsafePointer(), v.Len() * int(v.Type().Elem().Size()/t.Size())
+	}
+	return v.Addr().UnsafePointer(), int(v.Type().Size() / t.Size())
+}
+
+// hasVarLenData returns whether elements of dtype may refer to memory
+// allocated by the library for variable-length sequences or strings. All
+// datatypes holding strings are reported.
+func hasVarLenData(dtype *Datatype) bool {
+	return C.H5Tdetect_class(dtype.id, C.H5T_VLEN) > 0 || C.H5Tdetect_class(dtype.id, C.H5T_STRING) > 0
+}
+
+// readStaged reads n elements of Go type t with read through a buffer in C
+// memory into the Go values at addr, with the memory datatype mtype. Only
+// the elements selected in memspace are set, all of them if memspace is
+// nil. The strings and sequences allocated by the library are copied into
+// Go memory and reclaimed.
+func readStaged(addr unsafe.Pointer, n int, t reflect.Type, mtype *Datatype, memspace *Dataspace, read func(buf unsafe.Pointer) error) error {
+	layout, err := newMemLayout(t)
+	if err != nil {
+		return err
+	}
+	mask, err := selectionMask(memspace, n)
+	if err != nil {
+		return err
+	}
+	buf := newCBuffer(layout.size, n)
+	defer buf.free()
+	if err := read(buf.ptr); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if mask == nil || mask[i] != 0 {
+			layout.decode(unsafe.Add(addr, uintptr(i)*t.Size()), buf.at(i))
+		}
+	}
+	return buf.reclaim(mtype, memspace)
+}
+
+// writeStaged writes the n Go values of type t at addr with write through a
+// buffer in C memory.
+func writeStaged(addr unsafe.Pointer, n int, t reflect.Type, write func(buf unsafe.Pointer) error) error {
+	layout, err := newMemLayout(t)
+	if err != nil {
+		return err
+	}
+	buf := newCBuffer(layout.size, n)
+	defer buf.free()
+	buf.encode(layout, addr)
+	return write(buf.ptr)
+}
+
+// selectionMask returns whether each of the n elements of a buffer is
+// selected in space, as a non-zero byte, or nil if space is nil.
+func selectionMask(space *Dataspace, n int) ([]byte, error) {
+	defer lockThread()()
+	if space == nil || n == 0 {
+		return nil, nil
+	}
+	mask := make([]byte, n)
+	one := byte(1)
+	err := h5err(C.H5Dfill(unsafe.Pointer(&one), T_NATIVE_UINT8.id, unsafe.Pointer(&mask[0]), T_NATIVE_UINT8.id, space.id))
+	if err != nil {
+		return nil, err
+	}
+	return mask, nil
+}
+
+// memTypeOf returns the memory datatype of the Go type t after checking that
+// the elements of dtype, the datatype of the object described by what, may
+// be converted to and from it, down to the elements of sequences and arrays.
+// Enumerations are converted by member name to integer types.
+func memTypeOf(dtype *Datatype, t reflect.Type, what string) (*Datatype, error) {
+	mtype, err := NewDataTypeFromType(t)
+	if err != nil {
+		return nil, err
+	}
+	class, mclass := dtype.Class(), mtype.Class()
+	switch {
+	case convertibleTypes(dtype, mtype):
+		return mtype, nil
+	case class == T_ENUM && mclass == T_INTEGER:
+		defer mtype.Close()
+		enum, err := (&EnumType{*dtype}).memType(mtype)
+		if err != nil {
+			return nil, err
+		}
+		return &enum.Datatype, nil
+	}
+	mtype.Close()
+	return nil, fmt.Errorf("hdf5: cannot convert elements of %s of class %v to %v: %w", what, class, t, ErrBadType)
+}
@@ -0,0 +1,133 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+type stagedRecord struct {
+	Flag   int8
+	Name   string
+	Values []int32
+	Tags   [2]string
+	Weight float64
+	count  *int16
+}
+
+// cStagedRecord has the layout of stagedRecord in C memory, with strings
+// stored as char pointers, slices as hvl_t and pointers as their pointee.
+type cStagedRecord struct {
+	Flag   int8
+	Name   uintptr
+	Values [2]uintptr
+	Tags   [2]uintptr
+	Weight float64
+	count  int16
+}
+
+func TestMemLayout(t *testing.T) {
+	var c cStagedRecord
+	want := []uintptr{
+		unsafe.Offsetof(c.Flag),
+		unsafe.Offsetof(c.Name),
+		unsafe.Offsetof(c.Values),
+		unsafe.Offsetof(c.Tags),
+		unsafe.Offsetof(c.Weight),
+		unsafe.Offsetof(c.count),
+	}
+
+	typ := reflect.TypeOf(stagedRecord{})
+	layout, err := newMemLayout(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.fixed {
+		t.Error("unexpected fixed layout for a struct holding strings")
+	}
+	if got := layout.offsets; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong offsets: got %v, want %v", got, want)
+	}
+	if layout.size != unsafe.Sizeof(c) {
+		t.Errorf("wrong size: got %d, want %d", layout.size, unsafe.Sizeof(c))
+	}
+
+	dtype, err := NewDataTypeFromType(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	if dtype.Size() != uint(layout.size) {
+		t.Errorf("wrong datatype size: got %d, want %d", dtype.Size(), layout.size)
+	}
+	ct := CompoundType{*dtype}
+	for i, off := range want {
+		if got := ct.MemberOffset(i); got != int(off) {
+			t.Errorf("wrong offset for member %q: got %d, want %d", ct.MemberName(i), got, off)
+		}
+	}
+
+	fixed, err := newMemLayout(reflect.TypeOf(typedPoint{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fixed.fixed || fixed.size != unsafe.Sizeof(typedPoint{}) {
+		t.Errorf("unexpected layout for a fixed-size struct: %+v", fixed)
+	}
+
+	for _, v := range []interface{}{map[string]int{}, []interface{}{}, struct{ C chan int }{}} {
+		if _, err := newMemLayout(reflect.TypeOf(v)); err == nil {
+			t.Errorf("expected an error for %T", v)
+		}
+	}
+}
+
+func TestStagingRoundTrip(t *testing.T) {
+	counts := []int16{1, 2, 3}
+	want := []stagedRecord{
+		{Flag: 1, Name: "one", Values: []int32{1, 2, 3}, Tags: [2]string{"a", "b"}, Weight: 0.5, count: &counts[0]},
+		{Flag: 2, Values: []int32{}, Weight: -1, count: &counts[1]},
+		{Flag: 3, Name: "three", Values: []int32{4}, Tags: [2]string{"", "c"}, count: &counts[2]},
+	}
+	layout, err := newMemLayout(reflect.TypeOf(stagedRecord{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := newCBuffer(layout.size, len(want))
+	defer buf.free()
+	buf.encode(layout, unsafe.Pointer(&want[0]))
+
+	got := make([]stagedRecord, len(want))
+	buf.decode(layout, unsafe.Pointer(&got[0]))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong round trip:\ngot= %+v\nwant=%+v", got, want)
+	}
+	if got[0].count == want[0].count {
+		t.Error("pointer field was not copied")
+	}
+}
+
+func TestHasGoPointers(t *testing.T) {
+	for _, test := range []struct {
+		v    interface{}
+		want bool
+	}{
+		{v: int32(0), want: false},
+		{v: [3]float64{}, want: false},
+		{v: typedPoint{}, want: false},
+		{v: "", want: true},
+		{v: []int{}, want: true},
+		{v: [2]string{}, want: true},
+		{v: stagedRecord{}, want: true},
+		{v: (*int)(nil), want: true},
+	} {
+		if got := hasGoPointers(reflect.TypeOf(test.v)); got != test.want {
+			t.Errorf("unexpected result for %T: got %t, want %t", test.v, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// StringOptions control the conversion of fixed-length strings to and from
+// Go strings. The zero value trims the padding of the strings read and
+// fails to write strings longer than the fixed length.
+type StringOptions struct {
+	// KeepPadding keeps the padding of the strings read, so that they
+	// are as long as the fixed length. Otherwise they end at their first
+	// null byte, and space-padded strings lose their trailing spaces.
+	KeepPadding bool
+
+	// TrimSpace removes leading and trailing white space from the strings
+	// read, as strings.TrimSpace does.
+	TrimSpace bool
+
+	// Truncate truncates the strings written that are longer than the
+	// fixed length, at a character boundary. Null-terminated strings hold
+	// one byte less than the fixed length. Shorter strings are padded as
+	// defined by the datatype, with null bytes or spaces.
+	Truncate bool
+}
+
+// isFixedString returns whether dtype is a fixed-length string datatype.
+func isFixedString(dtype *Datatype) bool {
+	return dtype.Class() == T_STRING && C.H5Tis_variable_str(dtype.id) == 0
+}
+
+// trim returns the Go string of the fixed-length string b with padding pad.
+func (o StringOptions) trim(b []byte, pad StrPad) string {
+	if !o.KeepPadding {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		if pad == T_STR_SPACEPAD {
+			b = bytes.TrimRight(b, " ")
+		}
+	}
+	if o.TrimSpace {
+		b = bytes.TrimSpace(b)
+	}
+	return string(b)
+}
+
+// pad stores s into the fixed-length string b with padding pad.
+func (o StringOptions) pad(b []byte, s string, pad StrPad) error {
+	n := len(b)
+	if pad == T_STR_NULLTERM {
+		n--
+	}
+	if len(s) > n {
+		if !o.Truncate {
+			return fmt.Errorf("hdf5: string of length %d does not fit fixed-length string of length %d", len(s), n)
+		}
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		s = s[:n]
+	}
+	fill := byte(0)
+	if pad == T_STR_SPACEPAD {
+		fill = ' '
+	}
+	for i := copy(b, s); i < len(b); i++ {
+		b[i] = fill
+	}
+	return nil
+}
+
+// readFixedStrings reads n fixed-length strings of the memory datatype mtype
+// with read into the Go strings at addr. Only the strings selected in
+// memspace are set, all of them if memspace is nil.
+func readFixedStrings(addr unsafe.Pointer, n int, mtype *Datatype, memspace *Dataspace, opts StringOptions, read func(buf unsafe.Pointer) error) error {
+	mask, err := selectionMask(memspace, n)
+	if err != nil {
+		return err
+	}
+	size := int(mtype.Size())
+	buf := make([]byte, n*size)
+	if err := read(unsafe.Pointer(&buf[0])); err != nil {
+		return err
+	}
+	pad := mtype.StrPad()
+	strs := unsafe.Slice((*string)(addr), n)
+	for i := range strs {
+		if mask != nil && mask[i] == 0 {
+			continue
+		}
+		strs[i] = opts.trim(buf[i*size:(i+1)*size], pad)
+	}
+	return nil
+}
+
+// writeFixedStrings writes the n Go strings at addr as fixed-length strings
+// of the memory datatype mtype with write.
+func writeFixedStrings(addr unsafe.Pointer, n int, mtype *Datatype, opts StringOptions, write func(buf unsafe.Pointer) error) error {
+	size := int(mtype.Size())
+	buf := make([]byte, n*size)
+	pad := mtype.StrPad()
+	for i, s := range unsafe.Slice((*string)(addr), n) {
+		if err := opts.pad(buf[i*size:(i+1)*size], s, pad); err != nil {
+			return err
+		}
+	}
+	return write(unsafe.Pointer(&buf[0]))
+}
@@ -0,0 +1,57 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"testing"
+)
+
+func TestStringOptions(t *testing.T) {
+	for _, test := range []struct {
+		opts StringOptions
+		in   string
+		pad  StrPad
+		want string
+	}{
+		{in: "abc\x00\x00", pad: T_STR_NULLTERM, want: "abc"},
+		{in: "abc\x00d", pad: T_STR_NULLPAD, want: "abc"},
+		{in: " abc  ", pad: T_STR_SPACEPAD, want: " abc"},
+		{in: " abc  ", pad: T_STR_NULLPAD, want: " abc  "},
+		{opts: StringOptions{TrimSpace: true}, in: " abc  ", pad: T_STR_SPACEPAD, want: "abc"},
+		{opts: StringOptions{KeepPadding: true}, in: "abc\x00\x00", pad: T_STR_NULLTERM, want: "abc\x00\x00"},
+		{opts: StringOptions{KeepPadding: true}, in: "abc  ", pad: T_STR_SPACEPAD, want: "abc  "},
+	} {
+		if got := test.opts.trim([]byte(test.in), test.pad); got != test.want {
+			t.Errorf("unexpected trim of %q with %v and %+v: got %q, want %q", test.in, test.pad, test.opts, got, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		opts StringOptions
+		in   string
+		pad  StrPad
+		want string
+		err  bool
+	}{
+		{in: "ab", pad: T_STR_NULLTERM, want: "ab\x00\x00"},
+		{in: "ab", pad: T_STR_SPACEPAD, want: "ab  "},
+		{in: "abcd", pad: T_STR_NULLPAD, want: "abcd"},
+		{in: "abcd", pad: T_STR_NULLTERM, err: true},
+		{in: "abcde", pad: T_STR_SPACEPAD, err: true},
+		{opts: StringOptions{Truncate: true}, in: "abcd", pad: T_STR_NULLTERM, want: "abc\x00"},
+		{opts: StringOptions{Truncate: true}, in: "abcde", pad: T_STR_SPACEPAD, want: "abcd"},
+		{opts: StringOptions{Truncate: true}, in: "aé€", pad: T_STR_NULLPAD, want: "aé\x00"},
+	} {
+		b := make([]byte, 4)
+		err := test.opts.pad(b, test.in, test.pad)
+		if (err != nil) != test.err {
+			t.Errorf("unexpected error padding %q with %v and %+v: %v", test.in, test.pad, test.opts, err)
+			continue
+		}
+		if err == nil && string(b) != test.want {
+			t.Errorf("unexpected padding of %q with %v and %+v: got %q, want %q", test.in, test.pad, test.opts, b, test.want)
+		}
+	}
+}
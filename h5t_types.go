@@ -98,6 +98,7 @@ var (
 // OpenDatatype opens a named datatype. The returned datastype must
 // be closed by the user when it is no longer needed.
 func OpenDatatype(c CommonFG, name string, tapl_id int) (*Datatype, error) {
+	defer lockThread()()
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -117,6 +118,7 @@ func NewDatatype(id C.hid_t) *Datatype {
 // T_OPAQUE, T_ENUM or T_STRING, and size is the size of the new datatype in bytes.
 // The returned datatype must be closed by the user when it is no longer needed.
 func CreateDatatype(class TypeClass, size int) (*Datatype, error) {
+	defer lockThread()()
 	_, ok := parametricTypes[class]
 	if !ok {
 		return nil,
@@ -134,9 +136,56 @@ func CreateDatatype(class TypeClass, size int) (*Datatype, error) {
 	return NewDatatype(hid), nil
 }
 
-// GoType returns the reflect.Type associated with the Datatype's TypeClass
+// GoType returns the reflect.Type associated with the Datatype. Integer,
+// floating-point and enumeration datatypes map to the Go numeric type of the
+// same size and sign, other datatypes to the reflect.Type associated with
+// their TypeClass.
 func (t *Datatype) GoType() reflect.Type {
-	return typeClassToGoType[t.Class()]
+	switch class := t.Class(); class {
+	case T_INTEGER:
+		return intGoType(t.Size(), t.Sign())
+	case T_FLOAT:
+		switch t.Size() {
+		case 4:
+			return _go_float32_t
+		case 8:
+			return _go_float64_t
+		}
+		return typeClassToGoType[class]
+	case T_ENUM:
+		super, err := t.SuperType()
+		if err != nil {
+			return typeClassToGoType[class]
+		}
+		defer super.Close()
+		return super.GoType()
+	default:
+		return typeClassToGoType[class]
+	}
+}
+
+// intGoType returns the Go integer type of the given size in bytes and sign.
+func intGoType(size uint, sign Sign) reflect.Type {
+	signed := sign == T_SGN_2
+	switch {
+	case size == 1 && signed:
+		return _go_int8_t
+	case size == 1:
+		return _go_uint8_t
+	case size == 2 && signed:
+		return _go_int16_t
+	case size == 2:
+		return _go_uint16_t
+	case size == 4 && signed:
+		return _go_int32_t
+	case size == 4:
+		return _go_uint32_t
+	case size == 8 && signed:
+		return _go_int64_t
+	case size == 8:
+		return _go_uint64_t
+	}
+	return typeClassToGoType[T_INTEGER]
 }
 
 // Close releases a datatype.
@@ -166,6 +215,7 @@ func (t *Datatype) Copy() (*Datatype, error) {
 // copyDatatype should be called by any function wishing to return
 // an existing Datatype from a Dataset or Attribute.
 func copyDatatype(id C.hid_t) (*Datatype, error) {
+	defer lockThread()()
 	hid := C.H5Tcopy(id)
 	if err := checkID(hid); err != nil {
 		return nil, err
@@ -180,6 +230,7 @@ func (t *Datatype) Equal(o *Datatype) bool {
 
 // Lock locks a datatype.
 func (t *Datatype) Lock() error {
+	defer lockThread()()
 	return h5err(C.H5Tlock(t.id))
 }
 
@@ -190,10 +241,185 @@ func (t *Datatype) Size() uint {
 
 // SetSize sets the total size of a Datatype.
 func (t *Datatype) SetSize(sz int) error {
+	defer lockThread()()
 	err := C.H5Tset_size(t.id, C.size_t(sz))
 	return h5err(err)
 }
 
+// ByteOrder is the byte order of an atomic datatype.
+type ByteOrder C.H5T_order_t
+
+const (
+	T_ORDER_ERROR ByteOrder = C.H5T_ORDER_ERROR // Error
+	T_ORDER_LE    ByteOrder = C.H5T_ORDER_LE    // Little endian
+	T_ORDER_BE    ByteOrder = C.H5T_ORDER_BE    // Big endian
+	T_ORDER_VAX   ByteOrder = C.H5T_ORDER_VAX   // VAX mixed endian
+	T_ORDER_MIXED ByteOrder = C.H5T_ORDER_MIXED // Compound type with mixed member orders
+	T_ORDER_NONE  ByteOrder = C.H5T_ORDER_NONE  // No particular order (strings, bits, ...)
+)
+
+func (o ByteOrder) String() string {
+	switch o {
+	case T_ORDER_ERROR:
+		return "error"
+	case T_ORDER_LE:
+		return "little-endian"
+	case T_ORDER_BE:
+		return "big-endian"
+	case T_ORDER_VAX:
+		return "vax"
+	case T_ORDER_MIXED:
+		return "mixed"
+	case T_ORDER_NONE:
+		return "none"
+	default:
+		return fmt.Sprintf("ByteOrder(%d)", int(o))
+	}
+}
+
+// Sign is the sign scheme of an integer datatype.
+type Sign C.H5T_sign_t
+
+const (
+	T_SGN_ERROR Sign = C.H5T_SGN_ERROR // Error
+	T_SGN_NONE  Sign = C.H5T_SGN_NONE  // Unsigned
+	T_SGN_2     Sign = C.H5T_SGN_2     // Two's complement
+)
+
+func (s Sign) String() string {
+	switch s {
+	case T_SGN_ERROR:
+		return "error"
+	case T_SGN_NONE:
+		return "unsigned"
+	case T_SGN_2:
+		return "signed"
+	default:
+		return fmt.Sprintf("Sign(%d)", int(s))
+	}
+}
+
+// StrPad is the padding of fixed-length strings shorter than their datatype.
+type StrPad C.H5T_str_t
+
+const (
+	T_STR_ERROR    StrPad = C.H5T_STR_ERROR    // Error
+	T_STR_NULLTERM StrPad = C.H5T_STR_NULLTERM // Null terminated, like C
+	T_STR_NULLPAD  StrPad = C.H5T_STR_NULLPAD  // Padded with zeros
+	T_STR_SPACEPAD StrPad = C.H5T_STR_SPACEPAD // Padded with spaces, like Fortran
+)
+
+func (p StrPad) String() string {
+	switch p {
+	case T_STR_ERROR:
+		return "error"
+	case T_STR_NULLTERM:
+		return "nullterm"
+	case T_STR_NULLPAD:
+		return "nullpad"
+	case T_STR_SPACEPAD:
+		return "spacepad"
+	default:
+		return fmt.Sprintf("StrPad(%d)", int(p))
+	}
+}
+
+// CharSet is the character set of a string datatype.
+type CharSet C.H5T_cset_t
+
+const (
+	T_CSET_ERROR CharSet = C.H5T_CSET_ERROR // Error
+	T_CSET_ASCII CharSet = C.H5T_CSET_ASCII // US ASCII
+	T_CSET_UTF8  CharSet = C.H5T_CSET_UTF8  // UTF-8 Unicode
+)
+
+func (c CharSet) String() string {
+	switch c {
+	case T_CSET_ERROR:
+		return "error"
+	case T_CSET_ASCII:
+		return "ascii"
+	case T_CSET_UTF8:
+		return "utf-8"
+	default:
+		return fmt.Sprintf("CharSet(%d)", int(c))
+	}
+}
+
+// Order returns the byte order of an atomic datatype.
+func (t *Datatype) Order() ByteOrder {
+	return ByteOrder(C.H5Tget_order(t.id))
+}
+
+// SetOrder sets the byte order of an atomic datatype.
+func (t *Datatype) SetOrder(order ByteOrder) error {
+	defer lockThread()()
+	return h5err(C.H5Tset_order(t.id, C.H5T_order_t(order)))
+}
+
+// Precision returns the number of significant bits of an atomic datatype.
+func (t *Datatype) Precision() uint {
+	return uint(C.H5Tget_precision(t.id))
+}
+
+// Offset returns the bit offset of the first significant bit of an atomic
+// datatype, or -1 on error.
+func (t *Datatype) Offset() int {
+	return int(C.H5Tget_offset(t.id))
+}
+
+// Sign returns the sign scheme of an integer datatype.
+func (t *Datatype) Sign() Sign {
+	return Sign(C.H5Tget_sign(t.id))
+}
+
+// StrPad returns the padding of a fixed-length string datatype.
+func (t *Datatype) StrPad() StrPad {
+	return StrPad(C.H5Tget_strpad(t.id))
+}
+
+// SetStrPad sets the padding of a fixed-length string datatype.
+func (t *Datatype) SetStrPad(pad StrPad) error {
+	defer lockThread()()
+	return h5err(C.H5Tset_strpad(t.id, C.H5T_str_t(pad)))
+}
+
+// CharSet returns the character set of a string datatype.
+func (t *Datatype) CharSet() CharSet {
+	return CharSet(C.H5Tget_cset(t.id))
+}
+
+// SetCharSet sets the character set of a string datatype.
+func (t *Datatype) SetCharSet(cset CharSet) error {
+	defer lockThread()()
+	return h5err(C.H5Tset_cset(t.id, C.H5T_cset_t(cset)))
+}
+
+// NativeType returns the native memory datatype equivalent to the Datatype.
+// The returned datatype must be closed by the user when it is no longer needed.
+func (t *Datatype) NativeType() (*Datatype, error) {
+	defer lockThread()()
+	hid := C.H5Tget_native_type(t.id, C.H5T_DIR_DEFAULT)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	return NewDatatype(hid), nil
+}
+
+// SuperType returns the base datatype of an enumeration, array or
+// variable-length datatype. The returned datatype must be closed by the
+// user when it is no longer needed.
+func (t *Datatype) SuperType() (*Datatype, error) {
+	defer lockThread()()
+	hid := C.H5Tget_super(t.id)
+	if err := checkID(hid); err != nil {
+		return nil, err
+	}
+	return NewDatatype(hid), nil
+}
+
+// ArrayType is a datatype whose elements are fixed-size arrays of elements
+// of a base type.
 type ArrayType struct {
 	Datatype
 }
@@ -202,6 +428,7 @@ type ArrayType struct {
 // of the array and dims specify the dimensions of the array. The returned
 // arraytype must be closed by the user when it is no longer needed.
 func NewArrayType(base_type *Datatype, dims []int) (*ArrayType, error) {
+	defer lockThread()()
 	ndims := C.uint(len(dims))
 	c_dims := (*C.hsize_t)(unsafe.Pointer(&dims[0]))
 
@@ -235,6 +462,8 @@ func (t *ArrayType) ArrayDims() []int {
 	return dims
 }
 
+// VarLenType is a datatype whose elements are variable-length sequences of
+// elements of a base type.
 type VarLenType struct {
 	Datatype
 }
@@ -242,7 +471,12 @@ type VarLenType struct {
 // NewVarLenType creates a new VarLenType. the base_type specifies the element type
 // of the VarLenType. The returned variable length type must be closed by the user
 // when it is no longer needed.
+//
+// Variable-length sequences are read into and written from Go slices, such
+// as the elements of a [][]float64 or the []T fields of a struct, which are
+// staged in C memory as hvl_t values.
 func NewVarLenType(base_type *Datatype) (*VarLenType, error) {
+	defer lockThread()()
 	id := C.H5Tvlen_create(base_type.id)
 	if err := checkID(id); err != nil {
 		return nil, err
@@ -257,6 +491,8 @@ func (vl *VarLenType) IsVariableStr() bool {
 	return C.H5Tis_variable_str(vl.id) > 0
 }
 
+// CompoundType is a datatype whose elements are records of named members,
+// like Go structs.
 type CompoundType struct {
 	Datatype
 }
@@ -265,6 +501,7 @@ type CompoundType struct {
 // the compound datatype. The returned compound type must be closed by the user
 // when it is no longer needed.
 func NewCompoundType(size int) (*CompoundType, error) {
+	defer lockThread()()
 	id := C.H5Tcreate(C.H5T_class_t(T_COMPOUND), C.size_t(size))
 	if err := checkID(id); err != nil {
 		return nil, err
@@ -310,6 +547,7 @@ func (t *CompoundType) MemberOffset(mbr_idx int) int {
 // MemberType returns the datatype of the specified member. The returned
 // datatype must be closed by the user when it is no longer needed.
 func (t *CompoundType) MemberType(mbr_idx int) (*Datatype, error) {
+	defer lockThread()()
 	hid := C.H5Tget_member_type(t.id, C.uint(mbr_idx))
 	if err := checkID(hid); err != nil {
 		return nil, err
@@ -319,6 +557,7 @@ func (t *CompoundType) MemberType(mbr_idx int) (*Datatype, error) {
 
 // Insert adds a new member to a compound datatype.
 func (t *CompoundType) Insert(name string, offset int, field *Datatype) error {
+	defer lockThread()()
 	cname := C.CString(name)
 	defer C.free(unsafe.Pointer(cname))
 	return h5err(C.H5Tinsert(t.id, cname, C.size_t(offset), field.id))
@@ -329,15 +568,167 @@ func (t *CompoundType) Insert(name string, offset int, field *Datatype) error {
 // type on the disk. However, using this may require type conversions
 // on more machines, so may be a worse option.
 func (t *CompoundType) Pack() error {
+	defer lockThread()()
 	return h5err(C.H5Tpack(t.id))
 }
 
+// EnumType is a datatype whose elements are named integer values of a base
+// integer type.
+type EnumType struct {
+	Datatype
+}
+
+// NewEnumType creates a new EnumType. The base_type specifies the integer type
+// the values of the enumeration are stored as. The returned enum type must be
+// closed by the user when it is no longer needed.
+func NewEnumType(base_type *Datatype) (*EnumType, error) {
+	defer lockThread()()
+	id := C.H5Tenum_create(base_type.id)
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	t := &EnumType{Datatype{Identifier: Identifier{id}}}
+	return t, nil
+}
+
+// Insert adds a new member with the specified name and value to an enumeration
+// datatype. The value is converted to the base type of the enumeration.
+func (t *EnumType) Insert(name string, value int64) error {
+	defer lockThread()()
+	buf, err := t.toBase(value)
+	if err != nil {
+		return err
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return h5err(C.H5Tenum_insert(t.id, cname, unsafe.Pointer(&buf[0])))
+}
+
+// NMembers returns the number of members of an enumeration datatype.
+func (t *EnumType) NMembers() int {
+	return int(C.H5Tget_nmembers(t.id))
+}
+
+// MemberName returns the name of an enumeration datatype member.
+func (t *EnumType) MemberName(mbr_idx int) string {
+	c_name := C.H5Tget_member_name(t.id, C.uint(mbr_idx))
+	if c_name == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(c_name))
+	return C.GoString(c_name)
+}
+
+// MemberValue returns the value of an enumeration datatype member.
+func (t *EnumType) MemberValue(mbr_idx int) (int64, error) {
+	defer lockThread()()
+	buf, err := t.baseBuffer()
+	if err != nil {
+		return 0, err
+	}
+	if err := h5err(C.H5Tget_member_value(t.id, C.uint(mbr_idx), unsafe.Pointer(&buf[0]))); err != nil {
+		return 0, err
+	}
+	return t.fromBase(buf)
+}
+
+// ValueOf returns the value of the enumeration datatype member with the specified name.
+func (t *EnumType) ValueOf(name string) (int64, error) {
+	defer lockThread()()
+	buf, err := t.baseBuffer()
+	if err != nil {
+		return 0, err
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := h5err(C.H5Tenum_valueof(t.id, cname, unsafe.Pointer(&buf[0]))); err != nil {
+		return 0, fmt.Errorf("hdf5: no enumeration member named %q: %w", name, err)
+	}
+	return t.fromBase(buf)
+}
+
+// NameOf returns the name of the enumeration datatype member with the specified value.
+func (t *EnumType) NameOf(value int64) (string, error) {
+	for i, n := 0, t.NMembers(); i < n; i++ {
+		v, err := t.MemberValue(i)
+		if err != nil {
+			return "", err
+		}
+		if v == value {
+			return t.MemberName(i), nil
+		}
+	}
+	return "", fmt.Errorf("hdf5: no enumeration member with value %d", value)
+}
+
+// memType returns a copy of the enumeration with the same members and the
+// specified base type, used to let HDF5 convert enumeration values by name
+// when reading them into memory.
+func (t *EnumType) memType(base_type *Datatype) (*EnumType, error) {
+	mt, err := NewEnumType(base_type)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := 0, t.NMembers(); i < n; i++ {
+		v, err := t.MemberValue(i)
+		if err == nil {
+			err = mt.Insert(t.MemberName(i), v)
+		}
+		if err != nil {
+			mt.Close()
+			return nil, err
+		}
+	}
+	return mt, nil
+}
+
+// baseBuffer returns a buffer large enough to hold a value of the base type
+// of the enumeration or a native int64.
+func (t *EnumType) baseBuffer() ([]int64, error) {
+	sz := C.H5Tget_size(t.id)
+	if sz == 0 {
+		return nil, fmt.Errorf("hdf5: invalid enumeration datatype")
+	}
+	return make([]int64, 1+sz/8), nil
+}
+
+// toBase converts v to the representation of the base type of the enumeration.
+func (t *EnumType) toBase(v int64) ([]int64, error) {
+	defer lockThread()()
+	buf, err := t.baseBuffer()
+	if err != nil {
+		return nil, err
+	}
+	buf[0] = v
+	base := C.H5Tget_super(t.id)
+	if err := checkID(base); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(base)
+	err = h5err(C.H5Tconvert(T_NATIVE_INT64.id, base, 1, unsafe.Pointer(&buf[0]), nil, P_DEFAULT.id))
+	return buf, err
+}
+
+// fromBase converts buf from the representation of the base type of the
+// enumeration to an int64.
+func (t *EnumType) fromBase(buf []int64) (int64, error) {
+	defer lockThread()()
+	base := C.H5Tget_super(t.id)
+	if err := checkID(base); err != nil {
+		return 0, err
+	}
+	defer C.H5Tclose(base)
+	err := h5err(C.H5Tconvert(base, T_NATIVE_INT64.id, 1, unsafe.Pointer(&buf[0]), nil, P_DEFAULT.id))
+	return buf[0], err
+}
+
 type OpaqueDatatype struct {
 	Datatype
 }
 
 // SetTag tags an opaque datatype.
 func (t *OpaqueDatatype) SetTag(tag string) error {
+	defer lockThread()()
 	ctag := C.CString(tag)
 	defer C.free(unsafe.Pointer(ctag))
 	return h5err(C.H5Tset_tag(t.id, ctag))
@@ -366,10 +757,22 @@ func NewDataTypeFromType(t reflect.Type) (*Datatype, error) {
 	var dt *Datatype = nil
 	var err error
 
+	switch t {
+	case _go_reference_t:
+		return T_STD_REF_OBJ.Copy()
+	case _go_region_reference_t:
+		return T_STD_REF_DSETREG.Copy()
+	}
+
 	switch t.Kind() {
 
 	case reflect.Int:
-		dt, err = T_NATIVE_INT.Copy()
+		// Go int is as wide as a pointer, unlike C int.
+		if t.Size() == 8 {
+			dt, err = T_NATIVE_INT64.Copy()
+		} else {
+			dt, err = T_NATIVE_INT32.Copy()
+		}
 
 	case reflect.Int8:
 		dt, err = T_NATIVE_INT8.Copy()
@@ -384,7 +787,11 @@ func NewDataTypeFromType(t reflect.Type) (*Datatype, error) {
 		dt, err = T_NATIVE_INT64.Copy()
 
 	case reflect.Uint:
-		dt, err = T_NATIVE_UINT.Copy()
+		if t.Size() == 8 {
+			dt, err = T_NATIVE_UINT64.Copy()
+		} else {
+			dt, err = T_NATIVE_UINT32.Copy()
+		}
 
 	case reflect.Uint8:
 		dt, err = T_NATIVE_UINT8.Copy()
@@ -439,7 +846,13 @@ func NewDataTypeFromType(t reflect.Type) (*Datatype, error) {
 		dt = &sdt.Datatype
 
 	case reflect.Struct:
-		sz := int(t.Size())
+		// Structs holding strings or slices are laid out as they are staged
+		// in C memory.
+		layout, err := newMemLayout(t)
+		if err != nil {
+			return nil, err
+		}
+		sz := int(layout.size)
 		cdt, err := NewCompoundType(sz)
 		if err != nil {
 			return nil, err
@@ -456,7 +869,7 @@ func NewDataTypeFromType(t reflect.Type) (*Datatype, error) {
 			if field_dt.goPtrPathLen > ptrPathLen {
 				ptrPathLen = field_dt.goPtrPathLen
 			}
-			offset := int(f.Offset + 0)
+			offset := int(layout.offset(i))
 			if field_dt == nil {
 				return nil, fmt.Errorf("pb with field [%d-%s]", i, f.Name)
 			}
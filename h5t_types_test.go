@@ -4,7 +4,11 @@
 
 package hdf5
 
-import "testing"
+import (
+	"os"
+	"reflect"
+	"testing"
+)
 
 func TestSimpleDatatypes(t *testing.T) {
 	// Smoke tests for the simple datatypes
@@ -187,3 +191,203 @@ func TestCloseBehavior(t *testing.T) {
 	}
 	defer dtype.Close()
 }
+
+type cellState int32
+
+const (
+	cellPartial cellState = -1
+	cellWet     cellState = 0
+	cellDry     cellState = 1
+)
+
+func TestEnumDatatype(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+
+	f, err := CreateFile(fname, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(fname)
+	defer f.Close()
+
+	dtype, err := NewEnumType(T_NATIVE_INT8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtype.Close()
+	members := []struct {
+		name  string
+		value int64
+	}{{"WET", 0}, {"DRY", 1}, {"PARTIAL", -1}}
+	for _, m := range members {
+		if err := dtype.Insert(m.name, m.value); err != nil {
+			t.Fatalf("Insert(%q) failed: %v", m.name, err)
+		}
+	}
+	if err := dtype.Insert("WET", 2); err == nil {
+		t.Error("expected an error inserting a duplicate member name")
+	}
+
+	if got := dtype.Class(); got != T_ENUM {
+		t.Errorf("wrong class: got %v, want %v", got, T_ENUM)
+	}
+	if got, want := dtype.NMembers(), len(members); got != want {
+		t.Errorf("wrong number of members: got %d, want %d", got, want)
+	}
+	for i, m := range members {
+		if got := dtype.MemberName(i); got != m.name {
+			t.Errorf("MemberName(%d): got %q, want %q", i, got, m.name)
+		}
+		v, err := dtype.ValueOf(m.name)
+		if err != nil {
+			t.Errorf("ValueOf(%q) failed: %v", m.name, err)
+		}
+		if v != m.value {
+			t.Errorf("ValueOf(%q): got %d, want %d", m.name, v, m.value)
+		}
+		name, err := dtype.NameOf(m.value)
+		if err != nil || name != m.name {
+			t.Errorf("NameOf(%d): got %q, %v, want %q", m.value, name, err, m.name)
+		}
+	}
+	if _, err := dtype.ValueOf("FLOODED"); err == nil {
+		t.Error("expected an error for a missing member name")
+	}
+
+	dims := []uint{5}
+	space, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dset, err := f.CreateDataset("state", &dtype.Datatype, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	if err := dset.Write(&[]int8{0, 1, -1, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := dset.ReadEnumNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"WET", "DRY", "PARTIAL", "DRY", "WET"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("wrong names: got %q, want %q", names, want)
+	}
+
+	states := make([]cellState, 5)
+	if err := dset.ReadEnum(&states); err != nil {
+		t.Fatal(err)
+	}
+	if want := []cellState{cellWet, cellDry, cellPartial, cellDry, cellWet}; !reflect.DeepEqual(states, want) {
+		t.Errorf("wrong values: got %v, want %v", states, want)
+	}
+
+	if err := dset.ReadEnum(&[]float64{}); err == nil {
+		t.Error("expected an error reading an enumeration into floats")
+	}
+	if err := dset.ReadEnum(&[2]int8{}); err == nil {
+		t.Error("expected an error reading into a buffer that is too small")
+	}
+	if err := dset.ReadEnum([5]cellState{}); err == nil {
+		t.Error("expected an error reading into an array passed by value")
+	}
+}
+
+func TestDatatypeProperties(t *testing.T) {
+	for _, tc := range []struct {
+		dt        *Datatype
+		order     ByteOrder
+		precision uint
+		sign      Sign
+		goType    reflect.Type
+	}{
+		{T_STD_I32BE, T_ORDER_BE, 32, T_SGN_2, _go_int32_t},
+		{T_STD_U16LE, T_ORDER_LE, 16, T_SGN_NONE, _go_uint16_t},
+		{T_IEEE_F64BE, T_ORDER_BE, 64, T_SGN_ERROR, _go_float64_t},
+		{T_NATIVE_UINT8, T_ORDER_LE, 8, T_SGN_NONE, _go_uint8_t},
+	} {
+		if got := tc.dt.Order(); got != tc.order {
+			t.Errorf("%v: wrong order: got %v, want %v", tc.goType, got, tc.order)
+		}
+		if got := tc.dt.Precision(); got != tc.precision {
+			t.Errorf("%v: wrong precision: got %d, want %d", tc.goType, got, tc.precision)
+		}
+		if got := tc.dt.Offset(); got != 0 {
+			t.Errorf("%v: wrong offset: got %d, want 0", tc.goType, got)
+		}
+		if tc.dt.Class() == T_INTEGER {
+			if got := tc.dt.Sign(); got != tc.sign {
+				t.Errorf("%v: wrong sign: got %v, want %v", tc.goType, got, tc.sign)
+			}
+		}
+		if got := tc.dt.GoType(); got != tc.goType {
+			t.Errorf("wrong Go type: got %v, want %v", got, tc.goType)
+		}
+
+		native, err := tc.dt.NativeType()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := native.GoType(); got != tc.goType {
+			t.Errorf("wrong Go type for native type: got %v, want %v", got, tc.goType)
+		}
+		native.Close()
+	}
+
+	dt, err := T_STD_I32LE.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	if err := dt.SetOrder(T_ORDER_BE); err != nil {
+		t.Fatal(err)
+	}
+	if !dt.Equal(T_STD_I32BE) {
+		t.Error("expected a big-endian datatype after SetOrder")
+	}
+
+	str, err := T_C_S1.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer str.Close()
+	if got := str.StrPad(); got != T_STR_NULLTERM {
+		t.Errorf("wrong default padding: got %v, want %v", got, T_STR_NULLTERM)
+	}
+	if got := str.CharSet(); got != T_CSET_ASCII {
+		t.Errorf("wrong default character set: got %v, want %v", got, T_CSET_ASCII)
+	}
+	if err := str.SetStrPad(T_STR_SPACEPAD); err != nil {
+		t.Fatal(err)
+	}
+	if err := str.SetCharSet(T_CSET_UTF8); err != nil {
+		t.Fatal(err)
+	}
+	if got := str.StrPad(); got != T_STR_SPACEPAD {
+		t.Errorf("wrong padding: got %v, want %v", got, T_STR_SPACEPAD)
+	}
+	if got := str.CharSet(); got != T_CSET_UTF8 {
+		t.Errorf("wrong character set: got %v, want %v", got, T_CSET_UTF8)
+	}
+
+	enum, err := NewEnumType(T_STD_U16LE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enum.Close()
+	super, err := enum.SuperType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer super.Close()
+	if !super.Equal(T_STD_U16LE) {
+		t.Error("wrong super type for enumeration")
+	}
+	if got := enum.GoType(); got != _go_uint16_t {
+		t.Errorf("wrong Go type for enumeration: got %v, want %v", got, _go_uint16_t)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+//
+// extern size_t _go_hdf5_filter_cb(int slot, unsigned int flags, size_t cd_nelmts, unsigned int *cd_values, size_t nbytes, size_t *buf_size, void **buf);
+//
+// #define _GO_HDF5_FILTER(n) \
+//   static size_t _go_hdf5_filter_##n(unsigned int flags, size_t cd_nelmts, const unsigned int cd_values[], size_t nbytes, size_t *buf_size, void **buf) { \
+//     return _go_hdf5_filter_cb(n, flags, cd_nelmts, (unsigned int *)cd_values, nbytes, buf_size, buf); \
+//   }
+//
+// _GO_HDF5_FILTER(0)  _GO_HDF5_FILTER(1)  _GO_HDF5_FILTER(2)  _GO_HDF5_FILTER(3)
+// _GO_HDF5_FILTER(4)  _GO_HDF5_FILTER(5)  _GO_HDF5_FILTER(6)  _GO_HDF5_FILTER(7)
+// _GO_HDF5_FILTER(8)  _GO_HDF5_FILTER(9)  _GO_HDF5_FILTER(10) _GO_HDF5_FILTER(11)
+// _GO_HDF5_FILTER(12) _GO_HDF5_FILTER(13) _GO_HDF5_FILTER(14) _GO_HDF5_FILTER(15)
+//
+// static H5Z_func_t _go_hdf5_filters[] = {
+//   _go_hdf5_filter_0,  _go_hdf5_filter_1,  _go_hdf5_filter_2,  _go_hdf5_filter_3,
+//   _go_hdf5_filter_4,  _go_hdf5_filter_5,  _go_hdf5_filter_6,  _go_hdf5_filter_7,
+//   _go_hdf5_filter_8,  _go_hdf5_filter_9,  _go_hdf5_filter_10, _go_hdf5_filter_11,
+//   _go_hdf5_filter_12, _go_hdf5_filter_13, _go_hdf5_filter_14, _go_hdf5_filter_15,
+// };
+//
+// static inline herr_t _go_hdf5_H5Zregister(int slot, H5Z_filter_t id, const char *name) {
+//   H5Z_class2_t cls = {
+//     H5Z_CLASS_T_VERS, id, 1, 1, name, NULL, NULL, _go_hdf5_filters[slot],
+//   };
+//   return H5Zregister(&cls);
+// }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// FilterID identifies a filter of the HDF5 data pipeline.
+type FilterID C.H5Z_filter_t
+
+const (
+	Z_FILTER_ERROR       FilterID = C.H5Z_FILTER_ERROR       // No filter
+	Z_FILTER_NONE        FilterID = C.H5Z_FILTER_NONE        // Reserved indefinitely
+	Z_FILTER_DEFLATE     FilterID = C.H5Z_FILTER_DEFLATE     // Deflate (GNU gzip) compression
+	Z_FILTER_SHUFFLE     FilterID = C.H5Z_FILTER_SHUFFLE     // Shuffle of the bytes of the elements
+	Z_FILTER_FLETCHER32  FilterID = C.H5Z_FILTER_FLETCHER32  // Fletcher32 checksum
+	Z_FILTER_SZIP        FilterID = C.H5Z_FILTER_SZIP        // Szip compression
+	Z_FILTER_NBIT        FilterID = C.H5Z_FILTER_NBIT        // N-bit packing
+	Z_FILTER_SCALEOFFSET FilterID = C.H5Z_FILTER_SCALEOFFSET // Scale-offset compression
+	Z_FILTER_RESERVED    FilterID = C.H5Z_FILTER_RESERVED    // Filter IDs below this value are reserved for the library
+	Z_FILTER_MAX         FilterID = C.H5Z_FILTER_MAX         // Maximum filter ID
+)
+
+func (id FilterID) String() string {
+	switch id {
+	case Z_FILTER_ERROR:
+		return "error"
+	case Z_FILTER_NONE:
+		return "none"
+	case Z_FILTER_DEFLATE:
+		return "deflate"
+	case Z_FILTER_SHUFFLE:
+		return "shuffle"
+	case Z_FILTER_FLETCHER32:
+		return "fletcher32"
+	case Z_FILTER_SZIP:
+		return "szip"
+	case Z_FILTER_NBIT:
+		return "nbit"
+	case Z_FILTER_SCALEOFFSET:
+		return "scaleoffset"
+	default:
+		return fmt.Sprintf("FilterID(%d)", int(id))
+	}
+}
+
+// Flags of a filter in the pipeline.
+const (
+	Z_FLAG_MANDATORY uint = C.H5Z_FLAG_MANDATORY // Filter failures abort the I/O operation
+	Z_FLAG_OPTIONAL  uint = C.H5Z_FLAG_OPTIONAL  // Filter failures leave the data unfiltered
+)
+
+// ScaleType is the scaling method of the scale-offset filter.
+type ScaleType C.H5Z_SO_scale_type_t
+
+const (
+	Z_SO_FLOAT_DSCALE ScaleType = C.H5Z_SO_FLOAT_DSCALE // Floating-point data, keep factor decimal digits
+	Z_SO_FLOAT_ESCALE ScaleType = C.H5Z_SO_FLOAT_ESCALE // Floating-point data, exponent scaling (not implemented by HDF5)
+	Z_SO_INT          ScaleType = C.H5Z_SO_INT          // Integer data, keep factor bits
+)
+
+// Z_SO_INT_MINBITS_DEFAULT lets the scale-offset filter compute the number
+// of bits needed for integer data.
+const Z_SO_INT_MINBITS_DEFAULT int = C.H5Z_SO_INT_MINBITS_DEFAULT
+
+// Option masks of the szip filter.
+const (
+	SZIP_EC_OPTION_MASK uint = C.H5_SZIP_EC_OPTION_MASK // Entropy coding, for data with little structure
+	SZIP_NN_OPTION_MASK uint = C.H5_SZIP_NN_OPTION_MASK // Nearest neighbor coding, for continuous data
+)
+
+// FilterInfo describes a filter of the pipeline of a dataset creation
+// property list.
+type FilterInfo struct {
+	ID       FilterID
+	Name     string
+	Flags    uint   // Z_FLAG_MANDATORY or Z_FLAG_OPTIONAL
+	CDValues []uint // Auxiliary data of the filter
+
+	// EncodeEnabled and DecodeEnabled report whether the filter can be
+	// used for writing and reading with the current library.
+	EncodeEnabled bool
+	DecodeEnabled bool
+}
+
+// FilterAvailable returns whether the filter id is registered with the
+// library, either built-in or loaded as a plugin.
+func FilterAvailable(id FilterID) (bool, error) {
+	defer lockThread()()
+	avail := C.H5Zfilter_avail(C.H5Z_filter_t(id))
+	if err := h5err(C.herr_t(avail)); err != nil {
+		return false, err
+	}
+	return avail > 0, nil
+}
+
+// Filter is a filter of the HDF5 data pipeline implemented in Go. Encode is
+// called with the bytes of each chunk written to a dataset using the filter,
+// and Decode with the bytes of each chunk read back. Both get the auxiliary
+// data given to PropList.SetFilter and return the filtered bytes, which may
+// reuse the storage of chunk.
+type Filter interface {
+	Encode(chunk []byte, cdValues []uint) ([]byte, error)
+	Decode(chunk []byte, cdValues []uint) ([]byte, error)
+}
+
+// maxGoFilters is the number of Go filters that can be registered at once.
+const maxGoFilters = 16
+
+type goFilter struct {
+	id     FilterID
+	name   *C.char
+	filter Filter
+}
+
+// goFilters holds the registered Go filters, indexed by the slot of the C
+// function calling them back.
+var goFilters struct {
+	sync.RWMutex
+	slots [maxGoFilters]*goFilter
+}
+
+// RegisterFilter registers f as the filter id of the HDF5 data pipeline.
+// The filter can then be added to a dataset creation property list with
+// PropList.SetFilter, and is used transparently when reading datasets
+// created with it. Application-defined filters must use an id of at least
+// Z_FILTER_RESERVED, registering an id again replaces its filter.
+func RegisterFilter(id int, name string, f Filter) error {
+	defer lockThread()()
+	if id < int(Z_FILTER_RESERVED) || id > int(Z_FILTER_MAX) {
+		return fmt.Errorf("hdf5: invalid filter id %d, want %d to %d", id, Z_FILTER_RESERVED, Z_FILTER_MAX)
+	}
+	if f == nil {
+		return errors.New("hdf5: nil filter")
+	}
+
+	goFilters.Lock()
+	defer goFilters.Unlock()
+	slot := -1
+	for i, gf := range goFilters.slots {
+		if gf != nil && gf.id == FilterID(id) {
+			slot = i
+			break
+		}
+		if gf == nil && slot < 0 {
+			slot = i
+		}
+	}
+	if slot < 0 {
+		return fmt.Errorf("hdf5: too many Go filters registered, at most %d", maxGoFilters)
+	}
+
+	// HDF5 keeps a pointer to the name as long as the filter is registered.
+	c_name := C.CString(name)
+	if err := h5err(C._go_hdf5_H5Zregister(C.int(slot), C.H5Z_filter_t(id), c_name)); err != nil {
+		C.free(unsafe.Pointer(c_name))
+		return fmt.Errorf("hdf5: could not register filter %d: %w", id, err)
+	}
+	if old := goFilters.slots[slot]; old != nil {
+		C.free(unsafe.Pointer(old.name))
+	}
+	goFilters.slots[slot] = &goFilter{id: FilterID(id), name: c_name, filter: f}
+	return nil
+}
+
+// UnregisterFilter unregisters the filter id, which must not be used by an
+// open dataset.
+func UnregisterFilter(id int) error {
+	defer lockThread()()
+	if err := h5err(C.H5Zunregister(C.H5Z_filter_t(id))); err != nil {
+		return fmt.Errorf("hdf5: could not unregister filter %d: %w", id, err)
+	}
+
+	goFilters.Lock()
+	defer goFilters.Unlock()
+	for i, gf := range goFilters.slots {
+		if gf != nil && gf.id == FilterID(id) {
+			C.free(unsafe.Pointer(gf.name))
+			goFilters.slots[i] = nil
+		}
+	}
+	return nil
+}
+
+// runGoFilter runs the Go filter of slot on the nbytes of *buf, an HDF5
+// allocated buffer of *bufSize bytes, and returns the number of filtered
+// bytes stored back into *buf, or 0 on failure.
+func runGoFilter(slot int, flags uint, cdValues []uint, nbytes int, bufSize *C.size_t, buf *unsafe.Pointer) (n int) {
+	goFilters.RLock()
+	gf := goFilters.slots[slot]
+	goFilters.RUnlock()
+	if gf == nil {
+		return 0
+	}
+	defer func() {
+		if recover() != nil {
+			n = 0
+		}
+	}()
+
+	in := C.GoBytes(*buf, C.int(nbytes))
+	var (
+		out []byte
+		err error
+	)
+	if flags&C.H5Z_FLAG_REVERSE != 0 {
+		out, err = gf.filter.Decode(in, cdValues)
+	} else {
+		out, err = gf.filter.Encode(in, cdValues)
+	}
+	if err != nil || len(out) == 0 {
+		return 0
+	}
+
+	if len(out) > int(*bufSize) {
+		p := C.H5allocate_memory(C.size_t(len(out)), false)
+		if p == nil {
+			return 0
+		}
+		C.H5free_memory(*buf)
+		*buf = p
+		*bufSize = C.size_t(len(out))
+	}
+	copy(unsafe.Slice((*byte)(*buf), len(out)), out)
+	return len(out)
+}
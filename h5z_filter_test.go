@@ -0,0 +1,140 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hdf5
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// deltaFilter stores the byte-wise differences of a chunk, prefixed with the
+// length of the chunk so that encoded chunks are larger than their input.
+type deltaFilter struct {
+	encoded, decoded int
+	fail             bool
+}
+
+func (f *deltaFilter) Encode(chunk []byte, cdValues []uint) ([]byte, error) {
+	if f.fail {
+		return nil, errors.New("encode failure")
+	}
+	f.encoded++
+	out := make([]byte, 4+len(chunk))
+	binary.LittleEndian.PutUint32(out, uint32(len(chunk)))
+	var prev byte
+	for i, b := range chunk {
+		out[4+i] = b - prev
+		prev = b
+	}
+	return out, nil
+}
+
+func (f *deltaFilter) Decode(chunk []byte, cdValues []uint) ([]byte, error) {
+	f.decoded++
+	if len(chunk) < 4 || int(binary.LittleEndian.Uint32(chunk)) != len(chunk)-4 {
+		return nil, fmt.Errorf("invalid chunk of %d bytes", len(chunk))
+	}
+	out := chunk[4:]
+	var prev byte
+	for i, b := range out {
+		out[i] = b + prev
+		prev = out[i]
+	}
+	return out, nil
+}
+
+func TestRegisterFilter(t *testing.T) {
+	DisplayErrors(true)
+	defer DisplayErrors(false)
+	const id = 32100
+	var (
+		fn    = "test_go_filter.h5"
+		dsn   = "dset_go_filter"
+		dims  = []uint{100, 100}
+		cdims = []uint{10, 10}
+	)
+	defer os.Remove(fn)
+
+	if err := RegisterFilter(10, "reserved", &deltaFilter{}); err == nil {
+		t.Error("expected an error registering a reserved filter id")
+	}
+
+	filter := &deltaFilter{}
+	if err := RegisterFilter(id, "delta", filter); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterFilter(id)
+	if ok, err := FilterAvailable(id); err != nil || !ok {
+		t.Fatalf("expected filter %d to be available: %v", id, err)
+	}
+
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcpl.Close()
+	if err = dcpl.SetChunk(cdims); err != nil {
+		t.Fatal(err)
+	}
+	if err = dcpl.SetFilter(id, Z_FLAG_MANDATORY, []uint{7}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := dcpl.Filter(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ID != id || info.Name != "delta" {
+		t.Errorf("unexpected filter info: %+v", info)
+	}
+
+	data0, err := save(fn, dsn, dims, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.encoded != 100 {
+		t.Errorf("wrong number of encoded chunks: got %d, want 100", filter.encoded)
+	}
+	data1, err := load(fn, dsn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.decoded != 100 {
+		t.Errorf("wrong number of decoded chunks: got %d, want 100", filter.decoded)
+	}
+	if err := compare(data0, data1); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing mandatory filter fails the write once chunks are flushed
+	// from the chunk cache.
+	if err := RegisterFilter(id, "delta", &deltaFilter{fail: true}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := CreateFile(fn, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	space, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer space.Close()
+	dset, err := f.CreateDatasetWith(dsn, T_NATIVE_DOUBLE, space, dcpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dset.Close()
+	err = dset.Write(&data0[0])
+	if err == nil {
+		err = f.Flush(F_SCOPE_LOCAL)
+	}
+	if err == nil {
+		t.Error("expected an error writing with a failing filter")
+	}
+}
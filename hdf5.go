@@ -10,10 +10,12 @@ import "C"
 
 import (
 	"fmt"
+	"runtime"
 )
 
 // init initializes the hdf5 library
 func init() {
+	defer lockThread()()
 	err := h5err(C.H5open())
 	if err != nil {
 		err_str := fmt.Sprintf("pb calling H5open(): %s", err)
@@ -21,25 +23,35 @@ func init() {
 	}
 }
 
-// hdferror wraps hdf5 int-based error codes
-type h5error struct {
-	code int
-}
-
-func (h h5error) Error() string {
-	return fmt.Sprintf("code %d", h.code)
+// lockThread locks the calling goroutine to its OS thread and returns the
+// function that unlocks it. In thread-safe builds of HDF5 the error stack is
+// local to each thread, so functions that check the result of a call into
+// the library with h5err or checkID start with
+//
+//	defer lockThread()()
+//
+// to read the error stack on the thread of the failing call.
+func lockThread() func() {
+	runtime.LockOSThread()
+	return runtime.UnlockOSThread
 }
 
+// h5err returns an *ErrorStack if herr reports a failure of the last call
+// into the HDF5 library. The calling goroutine must be locked to its OS
+// thread since that call, see lockThread.
 func h5err(herr C.herr_t) error {
 	if herr < 0 {
-		return h5error{code: int(herr)}
+		return newErrorStack(int(herr))
 	}
 	return nil
 }
 
+// checkID returns an *ErrorStack if hid is not a valid identifier returned
+// by the last call into the HDF5 library. The calling goroutine must be
+// locked to its OS thread since that call, see lockThread.
 func checkID(hid C.hid_t) error {
 	if hid < 0 {
-		return h5error{code: int(hid)}
+		return newErrorStack(int(hid))
 	}
 	return nil
 }
@@ -47,6 +59,7 @@ func checkID(hid C.hid_t) error {
 // Close flushes all data to disk, closes all open identifiers, and cleans up memory.
 // It should generally be called before your application exits.
 func Close() error {
+	defer lockThread()()
 	return h5err(C.H5close())
 }
 
@@ -63,6 +76,7 @@ func (v Version) String() string {
 
 // LibVersion returns version information for the HDF5 library.
 func LibVersion() (Version, error) {
+	defer lockThread()()
 	var maj, min, rel C.uint
 	var v Version
 	err := h5err(C.H5get_libversion(&maj, &min, &rel))
@@ -76,12 +90,16 @@ func LibVersion() (Version, error) {
 
 // GarbageCollect collects garbage on all free-lists of all types.
 func GarbageCollect() error {
+	defer lockThread()()
 	return h5err(C.H5garbage_collect())
 }
 
-// Object represents an hdf5 object.
+// Object represents an hdf5 object. It is implemented by File, Group,
+// Dataset and Datatype.
 type Object interface {
 	Name() string
-	Id() int
+	ID() int64
 	File() *File
+	Info() (ObjectInfo, error)
+	Close() error
 }
@@ -11,7 +11,7 @@ func TestLibVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Could not get HDF5 library version: %s", err)
 	}
-	if v.Major < 1 || (v.Major == 1 && v.Minor < 8) {
-		t.Fatalf("go-hdf5 requires HDF5 > 1.8.0, detected %s", v)
+	if v.Major < 1 || (v.Major == 1 && (v.Minor < 10 || v.Minor == 10 && v.Release < 3)) {
+		t.Fatalf("go-hdf5 requires HDF5 >= 1.10.3, detected %s", v)
 	}
 }